@@ -0,0 +1,111 @@
+package fastrest
+
+import (
+	"errors"
+	"net"
+	"net/http/pprof"
+
+	"github.com/valyala/fasthttp"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+const PprofPath = "/debug/pprof"
+
+func (a *App) Admin() *Router {
+	return a.admin
+}
+
+func (a *App) opsRouter() *Router {
+	if a.admin != nil {
+		return a.admin
+	}
+	return a.router
+}
+
+func (a *App) registerAdminRoutes() {
+	r := a.admin.Group(PprofPath)
+	r.GET("/cmdline", WrapHTTPHandlerFunc(pprof.Cmdline)).Named("fastrest.pprof.cmdline")
+	r.GET("/profile", WrapHTTPHandlerFunc(pprof.Profile)).Named("fastrest.pprof.profile")
+	r.GET("/symbol", WrapHTTPHandlerFunc(pprof.Symbol)).Named("fastrest.pprof.symbol")
+	r.POST("/symbol", WrapHTTPHandlerFunc(pprof.Symbol))
+	r.GET("/trace", WrapHTTPHandlerFunc(pprof.Trace)).Named("fastrest.pprof.trace")
+	r.GET("/*", WrapHTTPHandlerFunc(pprof.Index)).Named("fastrest.pprof")
+
+	a.admin.GET(RoutesPath, func(c *context.Ctx) error {
+		return c.RawJSON(constant.StatusOK, a.Routes())
+	}).Named("fastrest.routes")
+}
+
+func (a *App) handleAdminRequest(fctx *fasthttp.RequestCtx) {
+	c := a.acquireCtx(fctx)
+	defer a.releaseCtx(c)
+
+	path, err := requestPath(fctx.URI().PathOriginal())
+	if err != nil {
+		c.BadRequest(err.Error())
+		return
+	}
+
+	buf := paramPool.Get().(*[]routeParam)
+	defer releaseParams(buf)
+	route, params := a.admin.find(string(fctx.Method()), string(fctx.Host()), path, (*buf)[:0])
+	*buf = params
+
+	if route == nil {
+		c.NotFound("not found")
+		return
+	}
+	for _, p := range params {
+		c.Params[p.key] = unescapeParam(p.value)
+	}
+
+	handler := adminHandler(route)
+	if err := handler(c); err != nil {
+		status := writeError(c, err)
+		if status >= constant.StatusInternalServerError {
+			c.Logger.Error("admin handler error", "error", err.Error(), "path", route.Path, "status", status)
+		}
+	}
+}
+
+func adminHandler(route *Route) context.Handler {
+	if compiled := route.chain.Load(); compiled != nil {
+		return compiled.handler
+	}
+	handler := func(c *context.Ctx) error { return nil }
+	if len(route.Handlers) > 0 {
+		handler = wrapMiddleware(route.middleware, composeHandlers(route.Handlers))
+	}
+	route.chain.Store(&compiledChain{handler: handler})
+	return handler
+}
+
+func (a *App) serveAdmin(errChan chan<- error) error {
+	listen := net.Listen
+	if a.config.Prefork {
+		listen = reuseportListen
+	}
+	ln, err := listen("tcp4", a.config.AdminAddr)
+	if err != nil {
+		return err
+	}
+
+	a.adminSrv = &fasthttp.Server{
+		Handler:      a.handleAdminRequest,
+		ReadTimeout:  a.config.ReadTimeout,
+		WriteTimeout: a.config.WriteTimeout,
+		IdleTimeout:  a.config.IdleTimeout,
+		Logger:       &fasthttpLogger{logger: a.logger},
+		ErrorHandler: a.handleServerError,
+	}
+	a.logger.Info("admin server listening", "addr", ln.Addr().String())
+
+	go func() {
+		if err := a.adminSrv.Serve(ln); err != nil && !errors.Is(err, net.ErrClosed) {
+			errChan <- err
+		}
+	}()
+	return nil
+}
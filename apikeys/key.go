@@ -0,0 +1,85 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformedKey = errors.New("apikeys: malformed key")
+	ErrInvalidKey   = errors.New("apikeys: invalid key")
+	ErrKeyExpired   = errors.New("apikeys: key is expired")
+	ErrKeyRevoked   = errors.New("apikeys: key is revoked")
+	ErrNotFound     = errors.New("apikeys: key not found")
+)
+
+type Key struct {
+	ID         string            `json:"id"`
+	Prefix     string            `json:"prefix"`
+	Hash       string            `json:"-"`
+	Owner      string            `json:"owner"`
+	Name       string            `json:"name,omitempty"`
+	Scopes     []string          `json:"scopes,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ExpiresAt  time.Time         `json:"expires_at,omitempty"`
+	LastUsedAt time.Time         `json:"last_used_at,omitempty"`
+	RevokedAt  time.Time         `json:"revoked_at,omitempty"`
+	ReplacedBy string            `json:"replaced_by,omitempty"`
+}
+
+func (k *Key) Active(now time.Time) error {
+	if !k.RevokedAt.IsZero() {
+		return ErrKeyRevoked
+	}
+	if !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt) {
+		return ErrKeyExpired
+	}
+	return nil
+}
+
+func (k *Key) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func Generate(prefix string) (plaintext, id, hash string, err error) {
+	idBytes := make([]byte, 8)
+	secret := make([]byte, 32)
+	if _, err = rand.Read(idBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	id = hex.EncodeToString(idBytes)
+	plaintext = prefix + "_" + id + "_" + hex.EncodeToString(secret)
+	return plaintext, id, Hash(plaintext), nil
+}
+
+func Parse(plaintext string) (prefix, id string, err error) {
+	i := strings.LastIndexByte(plaintext, '_')
+	if i <= 0 {
+		return "", "", ErrMalformedKey
+	}
+	rest := plaintext[:i]
+	j := strings.LastIndexByte(rest, '_')
+	if j <= 0 || j == len(rest)-1 {
+		return "", "", ErrMalformedKey
+	}
+	return rest[:j], rest[j+1:], nil
+}
+
+func Hash(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
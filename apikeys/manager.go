@@ -0,0 +1,154 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"fastrest/pkg/clock"
+)
+
+type Config struct {
+	Store         Store
+	Prefix        string
+	Clock         clock.Clock
+	TouchInterval time.Duration
+}
+
+type IssueOptions struct {
+	Name     string
+	Scopes   []string
+	Metadata map[string]string
+	TTL      time.Duration
+}
+
+type Manager struct {
+	store         Store
+	prefix        string
+	clock         clock.Clock
+	touchInterval time.Duration
+}
+
+func NewManager(cfg *Config) *Manager {
+	m := &Manager{
+		store:         cfg.Store,
+		prefix:        cfg.Prefix,
+		clock:         cfg.Clock,
+		touchInterval: cfg.TouchInterval,
+	}
+	if m.store == nil {
+		m.store = NewMemoryStore()
+	}
+	if m.prefix == "" {
+		m.prefix = "sk"
+	}
+	if m.clock == nil {
+		m.clock = clock.System
+	}
+	if m.touchInterval == 0 {
+		m.touchInterval = time.Minute
+	}
+	return m
+}
+
+func (m *Manager) Store() Store {
+	return m.store
+}
+
+func (m *Manager) Issue(ctx context.Context, owner string, opts IssueOptions) (string, *Key, error) {
+	plaintext, id, hash, err := Generate(m.prefix)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := m.clock.Now()
+	k := &Key{
+		ID:        id,
+		Prefix:    m.prefix,
+		Hash:      hash,
+		Owner:     owner,
+		Name:      opts.Name,
+		Scopes:    opts.Scopes,
+		Metadata:  opts.Metadata,
+		CreatedAt: now,
+	}
+	if opts.TTL > 0 {
+		k.ExpiresAt = now.Add(opts.TTL)
+	}
+	if err := m.store.Create(ctx, k); err != nil {
+		return "", nil, err
+	}
+	return plaintext, k, nil
+}
+
+func (m *Manager) Rotate(ctx context.Context, id string, grace time.Duration) (string, *Key, error) {
+	old, err := m.store.Get(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	now := m.clock.Now()
+	if err := old.Active(now); err != nil {
+		return "", nil, err
+	}
+
+	opts := IssueOptions{Name: old.Name, Scopes: old.Scopes, Metadata: old.Metadata}
+	if !old.ExpiresAt.IsZero() {
+		opts.TTL = old.ExpiresAt.Sub(old.CreatedAt)
+	}
+	plaintext, k, err := m.Issue(ctx, old.Owner, opts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	old.ReplacedBy = k.ID
+	if grace > 0 {
+		if end := now.Add(grace); old.ExpiresAt.IsZero() || end.Before(old.ExpiresAt) {
+			old.ExpiresAt = end
+		}
+	} else {
+		old.RevokedAt = now
+	}
+	if err := m.store.Update(ctx, old); err != nil {
+		return "", nil, err
+	}
+	return plaintext, k, nil
+}
+
+func (m *Manager) Revoke(ctx context.Context, id string) error {
+	k, err := m.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if k.RevokedAt.IsZero() {
+		k.RevokedAt = m.clock.Now()
+	}
+	return m.store.Update(ctx, k)
+}
+
+func (m *Manager) Verify(ctx context.Context, plaintext string) (*Key, error) {
+	prefix, id, err := Parse(plaintext)
+	if err != nil || prefix != m.prefix {
+		return nil, ErrInvalidKey
+	}
+	k, err := m.store.Get(ctx, id)
+	if err == ErrNotFound {
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(k.Hash), []byte(Hash(plaintext))) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	now := m.clock.Now()
+	if err := k.Active(now); err != nil {
+		return nil, err
+	}
+	if now.Sub(k.LastUsedAt) >= m.touchInterval {
+		if err := m.store.Touch(ctx, k.ID, now); err == nil {
+			k.LastUsedAt = now
+		}
+	}
+	return k, nil
+}
@@ -0,0 +1,80 @@
+package apikeys
+
+import (
+	"strings"
+
+	"fastrest/context"
+)
+
+const keyKey context.Key[*Key] = "apikeys.key"
+
+type MiddlewareConfig struct {
+	Header string
+	Query  string
+}
+
+func Middleware(m *Manager, cfg *MiddlewareConfig) context.Middleware {
+	header := "X-API-Key"
+	query := ""
+	if cfg != nil {
+		if cfg.Header != "" {
+			header = cfg.Header
+		}
+		query = cfg.Query
+	}
+
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			plaintext := c.Get(header)
+			if plaintext == "" {
+				if auth := c.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+					plaintext = auth[7:]
+				}
+			}
+			if plaintext == "" && query != "" {
+				plaintext = c.Query(query)
+			}
+			if plaintext == "" {
+				return c.Unauthorized("missing API key")
+			}
+
+			k, err := m.Verify(c.Context(), plaintext)
+			switch err {
+			case nil:
+			case ErrKeyExpired:
+				return c.Unauthorized("API key expired")
+			case ErrKeyRevoked:
+				return c.Unauthorized("API key revoked")
+			case ErrInvalidKey:
+				return c.Unauthorized("invalid API key")
+			default:
+				return err
+			}
+
+			keyKey.Set(c, k)
+			var claims map[string]interface{}
+			if len(k.Metadata) > 0 {
+				claims = make(map[string]interface{}, len(k.Metadata))
+				for name, v := range k.Metadata {
+					claims[name] = v
+				}
+			}
+			c.SetAuth(&context.AuthInfo{
+				Type:      "apikey",
+				Value:     k.ID,
+				Subject:   k.Owner,
+				Username:  k.Owner,
+				Scopes:    k.Scopes,
+				Claims:    claims,
+				ExpiresAt: k.ExpiresAt,
+				Valid:     true,
+			})
+			return next(c)
+		}
+	}
+}
+
+func FromCtx(c *context.Ctx) *Key {
+	k, _ := keyKey.Get(c)
+	return k
+}
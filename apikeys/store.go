@@ -0,0 +1,93 @@
+package apikeys
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Store interface {
+	Create(ctx context.Context, k *Key) error
+	Get(ctx context.Context, id string) (*Key, error)
+	Update(ctx context.Context, k *Key) error
+	List(ctx context.Context, owner string) ([]*Key, error)
+	Touch(ctx context.Context, id string, at time.Time) error
+}
+
+type MemoryStore struct {
+	mu   sync.RWMutex
+	keys map[string]*Key
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{keys: make(map[string]*Key)}
+}
+
+func (s *MemoryStore) Create(ctx context.Context, k *Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[k.ID] = clone(k)
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return clone(k), nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, k *Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[k.ID]; !ok {
+		return ErrNotFound
+	}
+	s.keys[k.ID] = clone(k)
+	return nil
+}
+
+func (s *MemoryStore) List(ctx context.Context, owner string) ([]*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []*Key
+	for _, k := range s.keys {
+		if owner == "" || k.Owner == owner {
+			out = append(out, clone(k))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func (s *MemoryStore) Touch(ctx context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.keys[id]
+	if !ok {
+		return ErrNotFound
+	}
+	k.LastUsedAt = at
+	return nil
+}
+
+func clone(k *Key) *Key {
+	c := *k
+	c.Scopes = append([]string(nil), k.Scopes...)
+	if k.Metadata != nil {
+		c.Metadata = make(map[string]string, len(k.Metadata))
+		for name, v := range k.Metadata {
+			c.Metadata[name] = v
+		}
+	}
+	return &c
+}
@@ -2,51 +2,130 @@ package fastrest
 
 import (
 	stdctx "context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/valyala/fasthttp"
 
+	"fastrest/assets"
 	"fastrest/constant"
 	"fastrest/context"
+	"fastrest/events"
 	"fastrest/metrics"
 	"fastrest/middlewares"
 	"fastrest/pkg/banner"
+	"fastrest/pkg/clock"
 	"fastrest/pkg/logging"
+	"fastrest/schedule"
+	"fastrest/worker"
 )
 
 type App struct {
 	config     *Config
 	router     *Router
+	admin      *Router
+	options    *Route
+	spas       []*Route
 	middleware []context.Middleware
+	chainGen   atomic.Uint64
 	server     *fasthttp.Server
+	adminSrv   *fasthttp.Server
+	h2server   *http.Server
 	logger     logging.Logger
 	metrics    *metrics.Metrics
 	startTime  time.Time
 	pool       sync.Pool
+	allocs     *allocSampler
+	readiness  *healthChecks
+	liveness   *healthChecks
+	recorder   metrics.Recorder
+	conns      *connTracker
+	workers    *worker.Pool
+	cron       *schedule.Scheduler
+	events     *events.Bus
+	memory     *memoryWatchdog
+	lifecycle  *lifecycle
+	timeouts   *timeoutTracker
+	assets     context.AssetResolver
+	proxies    *context.TrustedProxies
+	container  *container
+	modules    modules
+	dev        *devMode
+	redactor   *middlewares.Redactor
+	cacheInv   *middlewares.CacheInvalidator
+	onStop     []func(ctx stdctx.Context) error
+	clock      clock.Clock
 }
 
 type Config struct {
 	Addr               string
+	AdminAddr          string
 	ReadTimeout        time.Duration
 	WriteTimeout       time.Duration
 	IdleTimeout        time.Duration
 	MaxConnsPerIP      int
 	MaxRequestsPerConn int
+	MaxRequestBodySize int
+	TrustedProxies     []string
+	ProblemDetails     bool
+	Decode             *context.DecodeOptions
+	ResponseWrapper    context.ResponseWrapper
+	DisableAutoHead    bool
+	AutoOptions        bool
+	TrailingSlash      TrailingSlash
+	CaseInsensitive    bool
+	Views              context.Renderer
+	Features           context.FeatureChecker
+	HTTP2              bool
+	TLSCertFile        string
+	TLSKeyFile         string
 	Logger             logging.Logger
+	LogFormat          string
+	LogOutput          io.Writer
 	Metrics            bool
+	MetricsBuckets     []float64
+	MetricsExclude     []string
+	MetricsRecorder    metrics.Recorder
+	MetricsExemplars   bool
+	MetricsPath        string
+	MetricsMiddleware  []context.Middleware
 	LogMetrics         bool
+	AllocDiagnostics   bool
+	AllocSampleRate    int
 	HealthCheck        bool
 	HealthPath         string
+	HealthMiddleware   []context.Middleware
+	HealthCheckTimeout time.Duration
 	GracefulTimeout    time.Duration
+	Workers            int
+	WorkerQueueSize    int
+	MemoryWatchdog     bool
+	MemoryLimit        uint64
+	MemoryHighWater    float64
+	TimeoutPolicy      *TimeoutPolicy
+	Clock              clock.Clock
+	RoutesEndpoint     bool
+	ConfigEndpoint     bool
 	RequestLogger      bool
+	AccessLog          *middlewares.AccessLogConfig
+	Redact             *middlewares.RedactConfig
+	RequestID          bool
+	Prefork            bool
 	Banner             bool
 	Env                string
+	DevWatch           []string
+	DevWatchInterval   time.Duration
 }
 
 type HealthStatus struct {
@@ -54,6 +133,8 @@ type HealthStatus struct {
 	Uptime    string        `json:"uptime"`
 	Timestamp string        `json:"timestamp"`
 	System    *SystemHealth `json:"system,omitempty"`
+	Tasks     []TaskStatus  `json:"tasks,omitempty"`
+	Conns     *ConnStats    `json:"connections,omitempty"`
 }
 
 type SystemHealth struct {
@@ -83,33 +164,111 @@ func New(cfg *Config) *App {
 	if cfg.HealthPath == "" {
 		cfg.HealthPath = "/health"
 	}
+	if cfg.MetricsPath == "" {
+		cfg.MetricsPath = "/metrics"
+	}
+	if cfg.HealthCheckTimeout == 0 {
+		cfg.HealthCheckTimeout = 5 * time.Second
+	}
 	if cfg.GracefulTimeout == 0 {
 		cfg.GracefulTimeout = 10 * time.Second
 	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.System
+	}
 
 	var m *metrics.Metrics
 	if cfg.Metrics {
-		m = metrics.New()
+		m = metrics.NewWithBuckets(cfg.MetricsBuckets)
+		m.SetClock(cfg.Clock)
 	}
 
 	var logger logging.Logger
-	if cfg.Logger != nil {
+	switch {
+	case cfg.Logger != nil:
 		logger = cfg.Logger
-	} else {
-		logger = logging.NewLogger()
+	case cfg.LogFormat == "json":
+		jsonLogger := logging.NewJSONLogger(os.Stdout)
+		jsonLogger.SetClock(cfg.Clock)
+		if cfg.LogOutput != nil {
+			jsonLogger.SetOutput(cfg.LogOutput)
+		}
+		logger = jsonLogger
+	default:
+		consoleLogger := logging.NewLogger()
+		consoleLogger.SetClock(cfg.Clock)
+		if cfg.LogOutput != nil {
+			consoleLogger.SetOutput(cfg.LogOutput)
+		}
+		logger = consoleLogger
 	}
 
 	if cfg.LogMetrics && m != nil {
 		logger = logging.NewMetricsLogger(logger, m)
 	}
 
+	startTime := cfg.Clock.Now()
 	app := &App{
 		config:     cfg,
 		router:     newRouter(""),
 		middleware: make([]context.Middleware, 0),
 		logger:     logger,
 		metrics:    m,
-		startTime:  time.Now(),
+		recorder:   metrics.Multi(m, cfg.MetricsRecorder),
+		startTime:  startTime,
+		clock:      cfg.Clock,
+		lifecycle:  newLifecycle(startTime),
+		readiness:  newHealthChecks(),
+		liveness:   newHealthChecks(),
+		container:  newContainer(),
+		cacheInv:   middlewares.NewCacheInvalidator(),
+	}
+	app.router.fold = cfg.CaseInsensitive
+	app.router.cache = app.cacheInv
+
+	app.conns = newConnTracker(m, app.recorder)
+
+	app.workers = worker.New(&worker.Config{
+		Workers:   cfg.Workers,
+		QueueSize: cfg.WorkerQueueSize,
+		Logger:    logger,
+		Metrics:   m,
+		Clock:     cfg.Clock,
+	})
+	app.events = events.New(&events.Config{
+		Pool:    app.workers,
+		Logger:  logger,
+		Metrics: m,
+		Clock:   cfg.Clock,
+	})
+	app.cron = schedule.New(&schedule.Config{
+		Logger:  logger,
+		Metrics: m,
+		Clock:   cfg.Clock,
+	})
+
+	if cfg.MemoryWatchdog {
+		app.memory = newMemoryWatchdog(cfg.MemoryLimit, cfg.MemoryHighWater, time.Second, logger)
+		if app.memory == nil {
+			logger.Warn("memory watchdog disabled: no MemoryLimit or GOMEMLIMIT set")
+		} else {
+			app.AddReadinessCheck("memory", app.memoryCheck)
+			app.OnMemoryPressure(app.cacheInv.Purge)
+		}
+	}
+
+	if len(cfg.TrustedProxies) > 0 {
+		proxies, err := context.ParseTrustedProxies(cfg.TrustedProxies)
+		if err != nil {
+			logger.Warn("trusted proxies ignored", "error", err.Error())
+		} else {
+			app.proxies = proxies
+		}
+	}
+
+	if cfg.TimeoutPolicy != nil {
+		app.timeouts = newTimeoutTracker(cfg.TimeoutPolicy, cfg.Clock, logger, m)
+		app.AddReadinessCheck("timeouts", app.timeoutCheck)
 	}
 
 	app.pool.New = func() interface{} {
@@ -119,8 +278,47 @@ func New(cfg *Config) *App {
 		}
 	}
 
-	if cfg.RequestLogger {
-		app.Use(middlewares.RequestLogger())
+	if cfg.AllocDiagnostics && m != nil {
+		app.allocs = newAllocSampler(cfg.AllocSampleRate)
+	}
+
+	if cfg.RequestID {
+		app.Use(middlewares.RequestID())
+	}
+
+	app.redactor = middlewares.DefaultRedactor()
+	if cfg.Redact != nil {
+		app.redactor = middlewares.NewRedactor(cfg.Redact)
+	}
+
+	switch {
+	case cfg.AccessLog != nil:
+		accessLog := *cfg.AccessLog
+		if accessLog.Redactor == nil {
+			accessLog.Redactor = app.redactor
+		}
+		app.Use(middlewares.AccessLog(&accessLog))
+	case cfg.RequestLogger && cfg.LogFormat == "json":
+		app.Use(middlewares.AccessLog(&middlewares.AccessLogConfig{Output: cfg.LogOutput, Redactor: app.redactor}))
+	case cfg.RequestLogger:
+		app.Use(middlewares.RequestLoggerWithConfig(&middlewares.RequestLoggerConfig{Redactor: app.redactor}))
+	}
+
+	if cfg.AutoOptions {
+		app.registerAutoOptions()
+	}
+
+	if app.isDevelopment() {
+		app.dev = newDevMode(cfg.DevWatch, cfg.DevWatchInterval, logger)
+		app.dev.reloaded = func() { app.chainGen.Add(1) }
+		if v, ok := cfg.Views.(interface{ SetReload(bool) }); ok {
+			v.SetReload(true)
+		}
+	}
+
+	if cfg.AdminAddr != "" {
+		app.admin = newRouter("")
+		app.registerAdminRoutes()
 	}
 
 	if cfg.HealthCheck {
@@ -131,28 +329,55 @@ func New(cfg *Config) *App {
 		app.registerMetricsRoutes()
 	}
 
+	if cfg.RoutesEndpoint && app.admin == nil {
+		if app.isDevelopment() {
+			app.registerRoutesEndpoint()
+		} else {
+			logger.Warn("routes endpoint disabled outside development", "env", cfg.Env)
+		}
+	}
+
+	if cfg.ConfigEndpoint {
+		if app.admin != nil {
+			app.admin.GET(ConfigPath, app.ConfigHandler()).Named("fastrest.config")
+		} else {
+			logger.Warn("config endpoint disabled without AdminAddr; mount app.ConfigHandler() behind your own auth")
+		}
+	}
+
+	app.emit(EventConfigLoaded, map[string]interface{}{"addr": cfg.Addr, "env": cfg.Env})
+
 	return app
 }
 
 func (a *App) registerHealthRoutes() {
-	a.GET(a.config.HealthPath, a.healthHandler)
-	a.GET(a.config.HealthPath+"/live", a.liveHandler)
-	a.GET(a.config.HealthPath+"/ready", a.readyHandler)
+	r := a.opsRouter().Group(a.config.HealthPath)
+	r.Use(a.config.HealthMiddleware...)
+	r.GET("", a.healthHandler).Named("fastrest.health")
+	r.GET("/live", a.liveHandler).Named("fastrest.health.live")
+	r.GET("/ready", a.readyHandler).Named("fastrest.health.ready")
 }
 
 func (a *App) registerMetricsRoutes() {
-	a.GET("/metrics", a.metricsHandler)
-	a.GET("/metrics/json", a.metricsJSONHandler)
+	r := a.opsRouter().Group(a.config.MetricsPath)
+	r.Use(a.config.MetricsMiddleware...)
+	r.GET("", a.metricsHandler).Named("fastrest.metrics")
+	r.GET("/json", a.metricsJSONHandler).Named("fastrest.metrics.json")
 }
 
 func (a *App) healthHandler(c *context.Ctx) error {
 	var mem runtime.MemStats
 	runtime.ReadMemStats(&mem)
 
+	status := "ok"
+	if a.memory.underPressure() || len(a.timeouts.degraded()) > 0 {
+		status = "degraded"
+	}
+
 	health := &HealthStatus{
-		Status:    "ok",
-		Uptime:    time.Since(a.startTime).String(),
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Status:    status,
+		Uptime:    a.clock.Since(a.startTime).String(),
+		Timestamp: a.clock.Now().UTC().Format(time.RFC3339),
 		System: &SystemHealth{
 			GoVersion:    runtime.Version(),
 			NumCPU:       runtime.NumCPU(),
@@ -160,61 +385,141 @@ func (a *App) healthHandler(c *context.Ctx) error {
 			MemAlloc:     mem.Alloc,
 			MemSys:       mem.Sys,
 		},
+		Tasks: a.workers.Status(),
+	}
+	if a.server != nil || a.h2server != nil {
+		conns := a.conns.snapshot()
+		health.Conns = &conns
 	}
 
-	return c.JSON(constant.StatusOK, health)
+	return c.RawJSON(constant.StatusOK, health)
 }
 
 func (a *App) liveHandler(c *context.Ctx) error {
-	return c.JSON(constant.StatusOK, map[string]string{"status": "ok"})
+	return a.writeCheckReport(c, a.liveness.run(a.config.HealthCheckTimeout))
 }
 
 func (a *App) readyHandler(c *context.Ctx) error {
-	return c.JSON(constant.StatusOK, map[string]string{"status": "ok"})
+	return a.writeCheckReport(c, a.readiness.run(a.config.HealthCheckTimeout))
 }
 
 func (a *App) metricsHandler(c *context.Ctx) error {
+	if c.Accepts("text/plain", openMetricsType) == openMetricsType {
+		c.Status(constant.StatusOK)
+		c.Response.Header.SetContentType(openMetricsType + "; version=1.0.0; charset=utf-8")
+		c.Response.SetBodyString(a.metrics.ToOpenMetrics())
+		return nil
+	}
 	c.Set("Content-Type", "text/plain")
 	return c.String(constant.StatusOK, a.metrics.ToPrometheus())
 }
 
+const openMetricsType = "application/openmetrics-text"
+
+func traceIDFrom(c *context.Ctx) string {
+	if id, ok := context.TraceIDKey.Get(c); ok {
+		return id
+	}
+	parts := strings.Split(c.Get("traceparent"), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || parts[1] == strings.Repeat("0", 32) {
+		return ""
+	}
+	return parts[1]
+}
+
 func (a *App) metricsJSONHandler(c *context.Ctx) error {
-	return c.JSON(constant.StatusOK, a.metrics.ToJSON())
+	return c.RawJSON(constant.StatusOK, a.metrics.ToJSON())
 }
 
 func (a *App) Use(mw ...context.Middleware) {
 	a.middleware = append(a.middleware, mw...)
+	a.chainGen.Add(1)
 }
 
 func (a *App) handleRequest(fctx *fasthttp.RequestCtx) {
-	start := time.Now()
+	start := a.clock.Now()
 
 	c := a.acquireCtx(fctx)
 	defer a.releaseCtx(c)
 
 	method := string(fctx.Method())
-	path := string(fctx.Path())
+	path, err := requestPath(fctx.URI().PathOriginal())
+	if err != nil {
+		c.BadRequest(err.Error())
+		a.recordMetrics(c, method, unmatchedPath, constant.StatusBadRequest, a.clock.Since(start), "invalid_path")
+		return
+	}
+	defer a.markFirstRequest(method, path)
+
+	buf := paramPool.Get().(*[]routeParam)
+	defer releaseParams(buf)
+	route, params := a.lookup(method, string(fctx.Host()), path, (*buf)[:0])
+	redirect := ""
+	if route == nil && a.config.TrailingSlash != TrailingSlashStrict && path != "/" {
+		alt := toggleTrailingSlash(path)
+		route, params = a.lookup(method, string(fctx.Host()), alt, params[:0])
+		if route != nil && a.config.TrailingSlash == TrailingSlashRedirect {
+			if safeRedirectPath(alt) {
+				redirect = alt
+			} else {
+				route = nil
+			}
+		}
+	}
+	if route == nil && len(a.spas) > 0 && (method == "GET" || method == "HEAD") {
+		route, params = a.spaRoute(path, params[:0])
+	}
+	*buf = params
+
+	if a.shedLoad(c, path) {
+		a.recordMetrics(c, method, routeLabel(route), constant.StatusServiceUnavailable, a.clock.Since(start), "memory_pressure")
+		return
+	}
+
+	if redirect != "" {
+		status := a.redirectSlash(c, redirect)
+		a.recordMetrics(c, method, route.Path, status, a.clock.Since(start), "")
+		return
+	}
+
+	if route == nil && method == "OPTIONS" && a.options != nil {
+		if methods, pattern := a.router.allowedMethods(string(fctx.Host()), path); len(methods) > 0 {
+			a.serveAutoOptions(c, methods, pattern, start)
+			return
+		}
+	}
 
-	route, params := a.router.find(method, path)
 	if route == nil {
-		c.Status(constant.StatusNotFound).JSON(constant.StatusNotFound, map[string]string{"error": "not found"})
-		a.recordMetrics(method, path, constant.StatusNotFound, time.Since(start), "not_found")
+		c.NotFound("not found")
+		a.recordMetrics(c, method, unmatchedPath, constant.StatusNotFound, a.clock.Since(start), "not_found")
 		return
 	}
 
-	for k, v := range params {
-		c.Params[k] = v
+	for _, p := range params {
+		c.Params[p.key] = unescapeParam(p.value)
+	}
+	context.RoutePatternKey.Set(c, route.Path)
+
+	if a.allocs != nil && a.allocs.sample() {
+		sample := startAllocSample()
+		defer func() {
+			bytes, objects := sample.finish()
+			a.metrics.ObserveAllocs(method, route.Path, bytes, objects)
+		}()
 	}
 
-	handler := a.buildChain(route.Handlers, route.middleware)
-	if err := handler(c); err != nil {
-		a.logger.Error("handler error", "error", err.Error(), "path", path)
-		status := c.RequestCtx.Response.StatusCode()
-		if status == 0 {
-			status = constant.StatusInternalServerError
-			c.Status(status).JSON(status, map[string]string{"error": "internal server error"})
+	if err := a.callHandler(a.routeHandler(route), c); err != nil {
+		status := writeError(c, err)
+		if a.dev != nil && status >= constant.StatusInternalServerError {
+			a.describeError(c, status, err)
 		}
-		a.recordMetrics(method, route.Path, status, time.Since(start), "handler_error")
+		if status >= constant.StatusInternalServerError {
+			c.Logger.Error("handler error", "error", err.Error(), "path", path, "status", status)
+		} else {
+			c.Logger.Warn("handler error", "error", err.Error(), "path", path, "status", status)
+		}
+		a.recordMetrics(c, method, route.Path, status, a.clock.Since(start), "handler_error")
+		a.timeouts.record(method, route.Path, isTimeout(c, err, status))
 		return
 	}
 
@@ -222,25 +527,74 @@ func (a *App) handleRequest(fctx *fasthttp.RequestCtx) {
 	if status == 0 {
 		status = constant.StatusOK
 	}
-	a.recordMetrics(method, route.Path, status, time.Since(start), "")
+	a.recordMetrics(c, method, route.Path, status, a.clock.Since(start), "")
+	a.timeouts.record(method, route.Path, isTimeout(c, nil, status))
 }
 
-func (a *App) recordMetrics(method, path string, status int, duration time.Duration, errorType string) {
-	if a.metrics == nil {
+func (a *App) recordMetrics(c *context.Ctx, method, path string, status int, duration time.Duration, errorType string) {
+	if a.recorder == nil || a.excludedFromMetrics(path) {
 		return
 	}
-	a.metrics.IncRequestTotal(method, path, status)
-	a.metrics.ObserveLatency(method, path, duration)
+	traceID := ""
+	if a.config.MetricsExemplars {
+		traceID = traceIDFrom(c)
+	}
+	a.recorder.IncRequestTotal(method, path, status)
+	a.recorder.ObserveLatency(method, path, status, duration, traceID)
 	if errorType != "" {
-		a.metrics.IncError(method, path, errorType)
+		a.recorder.IncError(method, path, errorType)
 	}
 }
 
+const unmatchedPath = "unmatched"
+
+func routeLabel(route *Route) string {
+	if route == nil {
+		return unmatchedPath
+	}
+	return route.Path
+}
+
+func (a *App) excludedFromMetrics(path string) bool {
+	for _, pattern := range a.config.MetricsExclude {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if pattern == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) routeHandler(route *Route) context.Handler {
+	gen := a.chainGen.Load()
+	if compiled := route.chain.Load(); compiled != nil && compiled.gen == gen {
+		return compiled.handler
+	}
+	handler := a.buildChain(route.Handlers, route.middleware)
+	if route.meta.deprecated {
+		handler = a.deprecatedHandler(route, handler)
+	}
+	route.chain.Store(&compiledChain{gen: gen, handler: handler})
+	return handler
+}
+
+func releaseParams(buf *[]routeParam) {
+	clear(*buf)
+	*buf = (*buf)[:0]
+	paramPool.Put(buf)
+}
+
 func (a *App) buildChain(handlers []context.Handler, routeMiddleware []context.Middleware) context.Handler {
 	if len(handlers) == 0 {
 		return func(c *context.Ctx) error { return nil }
 	}
+	return wrapMiddleware(a.middleware, wrapMiddleware(routeMiddleware, composeHandlers(handlers)))
+}
 
+func composeHandlers(handlers []context.Handler) context.Handler {
 	final := handlers[len(handlers)-1]
 
 	for i := len(handlers) - 2; i >= 0; i-- {
@@ -253,12 +607,13 @@ func (a *App) buildChain(handlers []context.Handler, routeMiddleware []context.M
 			return next(c)
 		}
 	}
+	return final
+}
 
-	allMiddleware := append(a.middleware, routeMiddleware...)
-	for i := len(allMiddleware) - 1; i >= 0; i-- {
-		final = allMiddleware[i](final)
+func wrapMiddleware(middleware []context.Middleware, final context.Handler) context.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		final = middleware[i](final)
 	}
-
 	return final
 }
 
@@ -266,23 +621,34 @@ func (a *App) acquireCtx(fctx *fasthttp.RequestCtx) *context.Ctx {
 	c := a.pool.Get().(*context.Ctx)
 	c.RequestCtx = fctx
 	c.Logger = a.logger
-	for k := range c.Params {
-		delete(c.Params, k)
-	}
-	for k := range c.Locals {
-		delete(c.Locals, k)
-	}
+	c.SetRequestID("")
+	c.SetTimedOut(false)
+	c.SetAssets(a.assets)
+	c.SetViews(a.config.Views)
+	c.SetTranslator(nil)
+	c.SetResolver(a.container)
+	c.SetClock(a.clock)
+	c.SetContext(nil)
+	c.SetTrustedProxies(a.proxies)
+	c.SetProblemDetails(a.config.ProblemDetails)
+	c.SetDecodeOptions(a.config.Decode)
+	c.SetResponseWrapper(a.config.ResponseWrapper)
+	c.SetFeatures(a.config.Features)
 	return c
 }
 
 func (a *App) releaseCtx(c *context.Ctx) {
 	c.RequestCtx = nil
 	c.Logger = nil
+	c.Auth = nil
+	c.SetContext(nil)
+	clear(c.Params)
+	clear(c.Locals)
 	a.pool.Put(c)
 }
 
 func (a *App) Listen() error {
-	if a.config.Banner {
+	if a.config.Banner && !IsChild() {
 		banner.Print(&banner.Config{
 			Addr:        a.config.Addr,
 			HealthCheck: a.config.HealthCheck,
@@ -293,6 +659,10 @@ func (a *App) Listen() error {
 		})
 	}
 
+	if a.config.Prefork && !IsChild() {
+		return a.preforkMaster()
+	}
+
 	a.server = &fasthttp.Server{
 		Handler:            a.handleRequest,
 		ReadTimeout:        a.config.ReadTimeout,
@@ -300,19 +670,67 @@ func (a *App) Listen() error {
 		IdleTimeout:        a.config.IdleTimeout,
 		MaxConnsPerIP:      a.config.MaxConnsPerIP,
 		MaxRequestsPerConn: a.config.MaxRequestsPerConn,
+		MaxRequestBodySize: a.config.MaxRequestBodySize,
 		Logger:             &fasthttpLogger{logger: a.logger},
+		ErrorHandler:       a.handleServerError,
+		ConnState: func(conn net.Conn, state fasthttp.ConnState) {
+			a.conns.transition(conn, state.String())
+		},
+	}
+
+	a.emit(EventRoutesCompiled, map[string]interface{}{"routes": a.router.Count()})
+
+	if err := a.startModules(stdctx.Background()); err != nil {
+		a.stopModules(stdctx.Background())
+		return err
+	}
+
+	ln, err := a.listen()
+	if err != nil {
+		a.stopModules(stdctx.Background())
+		return err
+	}
+	a.emit(EventListenerBound, map[string]interface{}{"addr": ln.Addr().String(), "pid": os.Getpid()})
+
+	errChan := make(chan error, 2)
+	if a.admin != nil {
+		if err := a.serveAdmin(errChan); err != nil {
+			ln.Close()
+			a.stopModules(stdctx.Background())
+			return err
+		}
+	}
+
+	a.startDev()
+	if a.memory != nil {
+		a.memory.start()
 	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	if IsChild() {
+		runtime.GOMAXPROCS(1)
+		go watchMaster(quit)
+	}
+
+	if a.config.HTTP2 {
+		a.h2server = a.newHTTP2Server()
+	}
 
-	errChan := make(chan error, 1)
 	go func() {
-		errChan <- a.server.ListenAndServe(a.config.Addr)
+		switch {
+		case a.config.HTTP2:
+			errChan <- a.serveHTTP2(ln)
+		case a.config.TLSCertFile != "":
+			errChan <- a.server.ServeTLS(ln, a.config.TLSCertFile, a.config.TLSKeyFile)
+		default:
+			errChan <- a.server.Serve(ln)
+		}
 	}()
 
 	select {
 	case err := <-errChan:
+		a.stopModules(stdctx.Background())
 		if err != nil {
 			return err
 		}
@@ -323,23 +741,109 @@ func (a *App) Listen() error {
 }
 
 func (a *App) Shutdown() error {
+	a.emit(EventShutdownInitiated, nil)
+	began := a.clock.Now()
+
 	ctx, cancel := stdctx.WithTimeout(stdctx.Background(), a.config.GracefulTimeout)
 	defer cancel()
 
-	done := make(chan error, 1)
-	go func() {
-		done <- a.server.Shutdown()
-	}()
+	var err error
+	if a.h2server != nil {
+		err = a.h2server.Shutdown(ctx)
+		if errors.Is(err, stdctx.DeadlineExceeded) {
+			a.logger.Warn("graceful shutdown timeout, forcing close")
+			err = a.h2server.Close()
+		}
+	} else if a.server != nil {
+		done := make(chan error, 1)
+		go func() {
+			done <- a.server.Shutdown()
+		}()
+
+		select {
+		case <-ctx.Done():
+			a.logger.Warn("graceful shutdown timeout, forcing close")
+			err = a.server.Shutdown()
+		case err = <-done:
+		}
+	}
 
-	select {
-	case <-ctx.Done():
-		a.logger.Warn("graceful shutdown timeout, forcing close")
-		return a.server.Shutdown()
-	case err := <-done:
-		return err
+	if a.adminSrv != nil {
+		a.adminSrv.Shutdown()
+	}
+
+	if a.memory != nil {
+		a.memory.close()
+	}
+	if a.dev != nil {
+		a.dev.close()
+	}
+
+	a.events.Shutdown(ctx)
+	if werr := a.workers.Shutdown(ctx); werr != nil {
+		a.logger.Warn("background tasks did not drain before timeout", "error", werr.Error())
+	}
+	if cerr := a.cron.Shutdown(ctx); cerr != nil {
+		a.logger.Warn("cron jobs did not drain before timeout", "error", cerr.Error())
+	}
+	a.runShutdownHooks(ctx)
+	a.stopModules(ctx)
+	for _, cerr := range a.container.close() {
+		a.logger.Warn("provider failed to close", "error", cerr.Error())
+	}
+	if closer, ok := a.config.MetricsRecorder.(io.Closer); ok {
+		if merr := closer.Close(); merr != nil {
+			a.logger.Warn("metrics recorder failed to flush", "error", merr.Error())
+		}
+	}
+
+	a.emit(EventDrained, map[string]interface{}{"duration_ms": float64(a.clock.Since(began)) / float64(time.Millisecond)})
+	return err
+}
+
+func (a *App) OnShutdown(hook func(ctx stdctx.Context) error) {
+	a.lifecycle.mu.Lock()
+	a.onStop = append(a.onStop, hook)
+	a.lifecycle.mu.Unlock()
+}
+
+func (a *App) runShutdownHooks(ctx stdctx.Context) {
+	a.lifecycle.mu.Lock()
+	hooks := a.onStop
+	a.onStop = nil
+	a.lifecycle.mu.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			a.logger.Warn("shutdown hook failed", "error", err.Error())
+		}
 	}
 }
 
+func (a *App) Go(name string, task worker.Task) error {
+	return a.workers.Submit(name, task)
+}
+
+func (a *App) Every(name string, interval time.Duration, task worker.Task) error {
+	return a.workers.Every(name, interval, task)
+}
+
+func (a *App) Workers() *worker.Pool {
+	return a.workers
+}
+
+func (a *App) Events() *events.Bus {
+	return a.events
+}
+
+func (a *App) Cron(spec string, job schedule.Job, opts ...schedule.Option) error {
+	return a.cron.Add(spec, job, opts...)
+}
+
+func (a *App) Scheduler() *schedule.Scheduler {
+	return a.cron
+}
+
 func (a *App) GetLogger() logging.Logger {
 	return a.logger
 }
@@ -348,21 +852,99 @@ func (a *App) GetMetrics() *metrics.Metrics {
 	return a.metrics
 }
 
+func (a *App) Cache() *middlewares.CacheInvalidator {
+	return a.cacheInv
+}
+
+func (a *App) CacheMiddleware(ttl time.Duration, opts ...middlewares.CacheOption) context.Middleware {
+	return a.router.CacheMiddleware(ttl, opts...)
+}
+
+func (a *App) Redactor() *middlewares.Redactor {
+	return a.redactor
+}
+
 func (a *App) Uptime() time.Duration {
-	return time.Since(a.startTime)
+	return a.clock.Since(a.startTime)
 }
 
 func (a *App) Group(prefix string) *Router {
 	return a.router.Group(prefix)
 }
 
-func (a *App) GET(path string, handlers ...context.Handler)     { a.router.GET(path, handlers...) }
-func (a *App) POST(path string, handlers ...context.Handler)    { a.router.POST(path, handlers...) }
-func (a *App) PUT(path string, handlers ...context.Handler)     { a.router.PUT(path, handlers...) }
-func (a *App) PATCH(path string, handlers ...context.Handler)   { a.router.PATCH(path, handlers...) }
-func (a *App) DELETE(path string, handlers ...context.Handler)  { a.router.DELETE(path, handlers...) }
-func (a *App) HEAD(path string, handlers ...context.Handler)    { a.router.HEAD(path, handlers...) }
-func (a *App) OPTIONS(path string, handlers ...context.Handler) { a.router.OPTIONS(path, handlers...) }
+func (a *App) Host(pattern string) *Router {
+	return a.router.Host(pattern)
+}
+
+func (a *App) Assets(prefix, dir string) (*assets.Manifest, error) {
+	m, err := assets.Fingerprint(dir, prefix)
+	if err != nil {
+		return nil, err
+	}
+	a.assets = m
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	a.GET(prefix+"/*", m.Handler())
+	a.HEAD(prefix+"/*", m.Handler())
+	return m, nil
+}
+
+func (a *App) Mount(prefix string, sub *App) {
+	a.router.mount(prefix, sub.router, sub.middleware)
+}
+
+func (a *App) Handle(method, path string, handlers ...context.Handler) *Route {
+	return a.router.Handle(method, path, handlers...)
+}
+
+func (a *App) GET(path string, handlers ...context.Handler) *Route {
+	return a.router.GET(path, handlers...)
+}
+func (a *App) POST(path string, handlers ...context.Handler) *Route {
+	return a.router.POST(path, handlers...)
+}
+func (a *App) PUT(path string, handlers ...context.Handler) *Route {
+	return a.router.PUT(path, handlers...)
+}
+func (a *App) PATCH(path string, handlers ...context.Handler) *Route {
+	return a.router.PATCH(path, handlers...)
+}
+func (a *App) DELETE(path string, handlers ...context.Handler) *Route {
+	return a.router.DELETE(path, handlers...)
+}
+func (a *App) HEAD(path string, handlers ...context.Handler) *Route {
+	return a.router.HEAD(path, handlers...)
+}
+func (a *App) OPTIONS(path string, handlers ...context.Handler) *Route {
+	return a.router.OPTIONS(path, handlers...)
+}
+func (a *App) Any(path string, handlers ...context.Handler) {
+	a.router.Any(path, handlers...)
+}
+
+func (a *App) handleServerError(fctx *fasthttp.RequestCtx, err error) {
+	status := constant.StatusBadRequest
+	msg := "bad request"
+	if errors.Is(err, fasthttp.ErrBodyTooLarge) {
+		status = constant.StatusRequestEntityTooLarge
+		msg = "request body too large"
+	}
+
+	if a.config.ProblemDetails {
+		p := context.NewProblem(status, "", "", msg)
+		p.Instance = string(fctx.Path())
+		data, _ := json.Marshal(p)
+		fctx.Response.Header.SetContentType(context.ProblemContentType)
+		fctx.SetStatusCode(status)
+		fctx.SetBody(data)
+		return
+	}
+
+	data, _ := json.Marshal(map[string]string{"error": msg})
+	fctx.Response.Header.SetContentType("application/json")
+	fctx.SetStatusCode(status)
+	fctx.SetBody(data)
+}
 
 type fasthttpLogger struct {
 	logger logging.Logger
@@ -0,0 +1,119 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"fastrest/context"
+)
+
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+type Manifest struct {
+	dir    string
+	prefix string
+	paths  map[string]string
+	files  map[string]string
+}
+
+func Fingerprint(dir, prefix string) (*Manifest, error) {
+	m := &Manifest{
+		dir:    dir,
+		prefix: strings.TrimSuffix(prefix, "/"),
+		paths:  make(map[string]string),
+		files:  make(map[string]string),
+	}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		sum, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+		ext := path.Ext(name)
+		hashed := strings.TrimSuffix(name, ext) + "." + sum + ext
+		m.paths[name] = hashed
+		m.files[hashed] = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], nil
+}
+
+func (m *Manifest) Path(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if hashed, ok := m.paths[name]; ok {
+		return m.prefix + "/" + hashed
+	}
+	return m.prefix + "/" + name
+}
+
+func (m *Manifest) Entries() map[string]string {
+	entries := make(map[string]string, len(m.paths))
+	for name := range m.paths {
+		entries[name] = m.Path(name)
+	}
+	return entries
+}
+
+func (m *Manifest) WriteFile(filename string) error {
+	data, err := json.MarshalIndent(m.Entries(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o644)
+}
+
+func (m *Manifest) Handler() context.Handler {
+	return func(c *context.Ctx) error {
+		name := c.Param("*")
+		if file, ok := m.files[name]; ok {
+			c.Set("Cache-Control", ImmutableCacheControl)
+			return sendAsset(c, file)
+		}
+		if _, ok := m.paths[name]; ok {
+			c.Set("Cache-Control", "no-cache")
+			return sendAsset(c, filepath.Join(m.dir, filepath.FromSlash(name)))
+		}
+		return c.NotFound("not found")
+	}
+}
+
+func sendAsset(c *context.Ctx, file string) error {
+	if contentType := mime.TypeByExtension(filepath.Ext(file)); contentType != "" {
+		c.Response.Header.SetContentType(contentType)
+	}
+	return c.SendFile(file)
+}
@@ -0,0 +1,208 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrChainBroken = errors.New("audit: hash chain broken")
+
+type ChainError struct {
+	Index   int
+	EventID string
+	Reason  string
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("audit: hash chain broken at event %d (%s): %s", e.Index, e.EventID, e.Reason)
+}
+
+func (e *ChainError) Unwrap() error {
+	return ErrChainBroken
+}
+
+const chainQueueSize = 4096
+
+var (
+	ErrChainBacklogged = errors.New("audit: chain writer backlogged, event dropped")
+	ErrChainClosed     = errors.New("audit: chain closed, event dropped")
+)
+
+type HashChain struct {
+	key     []byte
+	mu      sync.Mutex
+	last    string
+	queue   chan func()
+	done    chan struct{}
+	closed  bool
+	dropped atomic.Uint64
+}
+
+func NewHashChain(key []byte) *HashChain {
+	return &HashChain{key: key}
+}
+
+func (h *HashChain) Resume(last string) {
+	h.mu.Lock()
+	h.last = last
+	h.mu.Unlock()
+}
+
+func (h *HashChain) Last() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.last
+}
+
+func (h *HashChain) Seal(e *Event) error {
+	return h.seal(e, nil)
+}
+
+func (h *HashChain) Dropped() uint64 {
+	return h.dropped.Load()
+}
+
+func (h *HashChain) seal(e *Event, emit func()) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if emit != nil {
+		switch {
+		case h.closed:
+			h.dropped.Add(1)
+			return ErrChainClosed
+		case h.queue != nil && len(h.queue) == cap(h.queue):
+			h.dropped.Add(1)
+			return ErrChainBacklogged
+		}
+	}
+	e.PrevHash = h.last
+	sum, err := eventHash(h.key, e)
+	if err != nil {
+		return err
+	}
+	e.Hash = sum
+	h.last = sum
+	if emit == nil {
+		return nil
+	}
+	if h.queue == nil {
+		h.queue = make(chan func(), chainQueueSize)
+		h.done = make(chan struct{})
+		go h.write()
+	}
+	h.queue <- emit
+	return nil
+}
+
+func (h *HashChain) write() {
+	defer close(h.done)
+	for emit := range h.queue {
+		emit()
+	}
+}
+
+func (h *HashChain) Close(ctx context.Context) error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	done := h.done
+	if h.queue != nil {
+		close(h.queue)
+	}
+	h.mu.Unlock()
+
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func Verify(events []*Event, key []byte) error {
+	prev := ""
+	for i, e := range events {
+		if err := verifyEvent(i, e, prev, key); err != nil {
+			return err
+		}
+		prev = e.Hash
+	}
+	return nil
+}
+
+func VerifyReader(r io.Reader, key []byte) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	prev := ""
+	n := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		var e Event
+		if err := dec.Decode(&e); err != nil {
+			return n, &ChainError{Index: n, Reason: "malformed event: " + err.Error()}
+		}
+		if err := verifyEvent(n, &e, prev, key); err != nil {
+			return n, err
+		}
+		prev = e.Hash
+		n++
+	}
+	return n, scanner.Err()
+}
+
+func verifyEvent(i int, e *Event, prev string, key []byte) error {
+	if i > 0 && e.PrevHash != prev {
+		return &ChainError{Index: i, EventID: e.ID, Reason: "prev_hash does not match previous event"}
+	}
+	sum, err := eventHash(key, e)
+	if err != nil {
+		return &ChainError{Index: i, EventID: e.ID, Reason: err.Error()}
+	}
+	if !hmac.Equal([]byte(sum), []byte(e.Hash)) {
+		return &ChainError{Index: i, EventID: e.ID, Reason: "hash mismatch"}
+	}
+	return nil
+}
+
+func eventHash(key []byte, e *Event) (string, error) {
+	unsealed := *e
+	unsealed.Hash = ""
+	data, err := json.Marshal(&unsealed)
+	if err != nil {
+		return "", err
+	}
+
+	var mac hash.Hash
+	if len(key) > 0 {
+		mac = hmac.New(sha256.New, key)
+	} else {
+		mac = sha256.New()
+	}
+	mac.Write([]byte(e.PrevHash))
+	mac.Write([]byte{'\n'})
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
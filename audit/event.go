@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+const SchemaVersion = "1"
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeDenied  = "denied"
+)
+
+type Event struct {
+	Version  string                 `json:"version"`
+	ID       string                 `json:"id"`
+	Time     time.Time              `json:"time"`
+	Tenant   string                 `json:"tenant,omitempty"`
+	Actor    Actor                  `json:"actor"`
+	Action   string                 `json:"action"`
+	Resource Resource               `json:"resource"`
+	Outcome  string                 `json:"outcome"`
+	Diff     map[string]Change      `json:"diff,omitempty"`
+	Request  RequestInfo            `json:"request"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	PrevHash string                 `json:"prev_hash,omitempty"`
+	Hash     string                 `json:"hash,omitempty"`
+}
+
+type Actor struct {
+	ID       string   `json:"id,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	AuthType string   `json:"auth_type,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+}
+
+type Resource struct {
+	Type string `json:"type,omitempty"`
+	ID   string `json:"id,omitempty"`
+}
+
+type Change struct {
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+type RequestInfo struct {
+	ID        string            `json:"id,omitempty"`
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	Route     string            `json:"route,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+	Status    int               `json:"status"`
+	IP        string            `json:"ip,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+}
+
+func NewEvent(action string) *Event {
+	return &Event{
+		Version: SchemaVersion,
+		ID:      newEventID(),
+		Time:    time.Now().UTC(),
+		Action:  action,
+		Outcome: OutcomeSuccess,
+	}
+}
+
+func (e *Event) SetDiff(field string, oldValue, newValue interface{}) {
+	if e.Diff == nil {
+		e.Diff = make(map[string]Change)
+	}
+	e.Diff[field] = Change{Old: oldValue, New: newValue}
+}
+
+func (e *Event) SetMetadata(key string, value interface{}) {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]interface{})
+	}
+	e.Metadata[key] = value
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,118 @@
+package audit
+
+import (
+	stdctx "context"
+	"time"
+
+	"fastrest/context"
+)
+
+const eventKey context.Key[*Event] = "audit.event"
+
+type Config struct {
+	Sinks    []Sink
+	Tenant   func(c *context.Ctx) string
+	Action   func(c *context.Ctx) string
+	Resource func(c *context.Ctx) Resource
+	Skip     func(c *context.Ctx) bool
+	Chain    *HashChain
+	Timeout  time.Duration
+}
+
+func Middleware(config *Config) context.Middleware {
+	cfg := &Config{}
+	if config != nil {
+		*cfg = *config
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			if cfg.Skip != nil && cfg.Skip(c) {
+				return next(c)
+			}
+
+			action := c.Method() + " " + c.Path()
+			if cfg.Action != nil {
+				action = cfg.Action(c)
+			}
+
+			e := NewEvent(action)
+			e.Time = c.Now().UTC()
+			e.Request = RequestInfo{
+				ID:        c.RequestID(),
+				Method:    c.Method(),
+				Path:      c.Path(),
+				IP:        c.IP(),
+				UserAgent: string(c.UserAgent()),
+			}
+			e.Request.Route, _ = context.RoutePatternKey.Get(c)
+			if len(c.Params) > 0 {
+				e.Request.Params = make(map[string]string, len(c.Params))
+				for k, v := range c.Params {
+					e.Request.Params[k] = v
+				}
+			}
+			if cfg.Tenant != nil {
+				e.Tenant = cfg.Tenant(c)
+			} else {
+				e.Tenant = c.Tenant()
+			}
+			if cfg.Resource != nil {
+				e.Resource = cfg.Resource(c)
+			}
+			eventKey.Set(c, e)
+
+			err := next(c)
+
+			if auth := c.GetAuth(); auth != nil && auth.Valid && e.Actor.ID == "" {
+				e.Actor.AuthType = auth.Type
+				e.Actor.ID = auth.Subject
+				if e.Actor.ID == "" {
+					e.Actor.ID = auth.Username
+				}
+				e.Actor.Roles = auth.Roles
+				if e.Actor.Type == "" {
+					e.Actor.Type = "user"
+				}
+			}
+
+			status := context.ErrorStatus(c, err)
+			e.Request.Status = status
+			if e.Outcome == OutcomeSuccess {
+				switch {
+				case status == 401 || status == 403:
+					e.Outcome = OutcomeDenied
+				case err != nil || status >= 400:
+					e.Outcome = OutcomeFailure
+				}
+			}
+
+			logger := c.GetLogger()
+			emit := func() {
+				ctx, cancel := stdctx.WithTimeout(stdctx.Background(), cfg.Timeout)
+				defer cancel()
+				for _, sink := range cfg.Sinks {
+					if sinkErr := sink.Emit(ctx, e); sinkErr != nil {
+						logger.Error("audit sink failed", "error", sinkErr.Error(), "event_id", e.ID)
+					}
+				}
+			}
+			if cfg.Chain != nil {
+				if sealErr := cfg.Chain.seal(e, emit); sealErr != nil {
+					logger.Error("audit chain failed", "error", sealErr.Error(), "event_id", e.ID)
+				}
+			} else {
+				emit()
+			}
+
+			return err
+		}
+	}
+}
+
+func FromCtx(c *context.Ctx) *Event {
+	e, _ := eventKey.Get(c)
+	return e
+}
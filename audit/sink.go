@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"fastrest/pkg/logging"
+)
+
+type Sink interface {
+	Emit(ctx context.Context, e *Event) error
+}
+
+type SinkFunc func(ctx context.Context, e *Event) error
+
+func (f SinkFunc) Emit(ctx context.Context, e *Event) error {
+	return f(ctx, e)
+}
+
+type LogSink struct {
+	logger logging.Logger
+}
+
+func NewLogSink(logger logging.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Emit(ctx context.Context, e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("audit", "event", json.RawMessage(data))
+	return nil
+}
+
+type WebhookSink struct {
+	url     string
+	client  *http.Client
+	headers map[string]string
+}
+
+func NewWebhookSink(url string, headers map[string]string) *WebhookSink {
+	return &WebhookSink{
+		url:     url,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		headers: headers,
+	}
+}
+
+func (s *WebhookSink) Emit(ctx context.Context, e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audit: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type Producer interface {
+	Produce(ctx context.Context, topic string, key, value []byte) error
+}
+
+type KafkaSink struct {
+	producer Producer
+	topic    string
+}
+
+func NewKafkaSink(producer Producer, topic string) *KafkaSink {
+	return &KafkaSink{producer: producer, topic: topic}
+}
+
+func (s *KafkaSink) Emit(ctx context.Context, e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	key := e.Tenant
+	if key == "" {
+		key = e.Actor.ID
+	}
+	return s.producer.Produce(ctx, s.topic, []byte(key), data)
+}
+
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Emit(ctx context.Context, e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+type FileSink struct {
+	WriterSink
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("audit: open file sink: %w", err)
+	}
+	return &FileSink{WriterSink: WriterSink{w: f}, file: f}, nil
+}
+
+func (s *FileSink) Emit(ctx context.Context, e *Event) error {
+	if err := s.WriterSink.Emit(ctx, e); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+type ChannelSink struct {
+	ch chan<- *Event
+}
+
+func NewChannelSink(ch chan<- *Event) *ChannelSink {
+	return &ChannelSink{ch: ch}
+}
+
+func (s *ChannelSink) Emit(ctx context.Context, e *Event) error {
+	select {
+	case s.ch <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,305 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"fastrest/context"
+	fcrypto "fastrest/crypto"
+)
+
+const stateCookie = "oidc_state"
+
+var ErrNoTokens = errors.New("oidc: no tokens stored")
+
+type Tokens struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	IDToken      string    `json:"id_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+type TokenStore interface {
+	Save(c *context.Ctx, t *Tokens) error
+	Load(c *context.Ctx) (*Tokens, error)
+	Clear(c *context.Ctx) error
+}
+
+type CookieStore struct {
+	Name   string
+	Keys   *fcrypto.KeyRing
+	Secure bool
+	MaxAge time.Duration
+}
+
+func NewCookieStore(keys *fcrypto.KeyRing) *CookieStore {
+	return &CookieStore{
+		Name:   "oidc_session",
+		Keys:   keys,
+		Secure: true,
+		MaxAge: 24 * time.Hour,
+	}
+}
+
+func (s *CookieStore) Save(c *context.Ctx, t *Tokens) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return setSealedCookie(c, s.Keys, s.Name, data, s.MaxAge, s.Secure)
+}
+
+func (s *CookieStore) Load(c *context.Ctx) (*Tokens, error) {
+	data, err := sealedCookie(c, s.Keys, s.Name)
+	if err != nil {
+		return nil, err
+	}
+	var t Tokens
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *CookieStore) Clear(c *context.Ctx) error {
+	clearCookie(c, s.Name)
+	return nil
+}
+
+type loginState struct {
+	State    string `json:"s"`
+	Verifier string `json:"v"`
+	Nonce    string `json:"n"`
+	ReturnTo string `json:"r"`
+}
+
+func (p *Provider) LoginHandler() context.Handler {
+	return func(c *context.Ctx) error {
+		if p.cfg.Store == nil {
+			return errors.New("oidc: Config.Store is required for the login flow")
+		}
+		d, err := p.Discover(c.Context())
+		if err != nil {
+			return err
+		}
+
+		st := loginState{
+			State:    randomString(),
+			Verifier: randomString() + randomString(),
+			Nonce:    randomString(),
+			ReturnTo: safeReturnTo(c.Query("return_to")),
+		}
+		data, _ := json.Marshal(st)
+		if err := setSealedCookie(c, p.stateKeys(), stateCookie, data, 10*time.Minute, p.secureCookies()); err != nil {
+			return err
+		}
+
+		challenge := sha256.Sum256([]byte(st.Verifier))
+		q := url.Values{
+			"response_type":         {"code"},
+			"client_id":             {p.cfg.ClientID},
+			"redirect_uri":          {p.cfg.RedirectURL},
+			"scope":                 {strings.Join(append([]string{"openid"}, p.cfg.Scopes...), " ")},
+			"state":                 {st.State},
+			"nonce":                 {st.Nonce},
+			"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+			"code_challenge_method": {"S256"},
+		}
+		sep := "?"
+		if strings.Contains(d.AuthorizationEndpoint, "?") {
+			sep = "&"
+		}
+		return c.Redirect(d.AuthorizationEndpoint+sep+q.Encode(), fasthttp.StatusFound)
+	}
+}
+
+func (p *Provider) CallbackHandler() context.Handler {
+	return func(c *context.Ctx) error {
+		if e := c.Query("error"); e != "" {
+			return c.Unauthorized("login failed: " + e)
+		}
+
+		data, err := sealedCookie(c, p.stateKeys(), stateCookie)
+		clearCookie(c, stateCookie)
+		if err != nil {
+			return c.BadRequest("missing login state")
+		}
+		var st loginState
+		if err := json.Unmarshal(data, &st); err != nil || st.State == "" || st.State != c.Query("state") {
+			return c.BadRequest("invalid login state")
+		}
+
+		tokens, err := p.exchange(c, c.Query("code"), st.Verifier)
+		if err != nil {
+			c.GetLogger().Error("oidc code exchange failed", "error", err.Error())
+			return c.Unauthorized("login failed")
+		}
+		claims, err := p.verify(c.Context(), tokens.IDToken, p.cfg.ClientID)
+		if err != nil {
+			return c.Unauthorized("invalid id token")
+		}
+		if claims.String("nonce") != st.Nonce {
+			return c.Unauthorized("invalid id token")
+		}
+
+		if err := p.cfg.Store.Save(c, tokens); err != nil {
+			return err
+		}
+		return c.Redirect(st.ReturnTo, fasthttp.StatusFound)
+	}
+}
+
+func (p *Provider) LogoutHandler(redirect string) context.Handler {
+	if redirect == "" {
+		redirect = "/"
+	}
+	return func(c *context.Ctx) error {
+		if err := p.cfg.Store.Clear(c); err != nil {
+			return err
+		}
+		return c.Redirect(redirect, fasthttp.StatusFound)
+	}
+}
+
+func (p *Provider) RequireLogin(loginPath string) context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			tokens, err := p.cfg.Store.Load(c)
+			if err == nil {
+				var claims Claims
+				claims, err = p.verify(c.Context(), tokens.IDToken, p.cfg.ClientID)
+				if err == nil {
+					setAuth(c, "oidc", tokens.AccessToken, claims)
+					return next(c)
+				}
+			}
+			return c.Redirect(loginPath+"?return_to="+url.QueryEscape(string(c.RequestURI())), fasthttp.StatusFound)
+		}
+	}
+}
+
+func (p *Provider) exchange(c *context.Ctx, code, verifier string) (*Tokens, error) {
+	d, err := p.Discover(c.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(c.Context(), http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	resp, err := p.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: token endpoint returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Tokens
+		ExpiresIn int64 `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.IDToken == "" {
+		return nil, errors.New("oidc: token response has no id_token")
+	}
+	t := body.Tokens
+	if body.ExpiresIn > 0 {
+		t.Expiry = p.cfg.Clock.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return &t, nil
+}
+
+func (p *Provider) stateKeys() *fcrypto.KeyRing {
+	if store, ok := p.cfg.Store.(*CookieStore); ok {
+		return store.Keys
+	}
+	return nil
+}
+
+func (p *Provider) secureCookies() bool {
+	if store, ok := p.cfg.Store.(*CookieStore); ok {
+		return store.Secure
+	}
+	return true
+}
+
+func setSealedCookie(c *context.Ctx, keys *fcrypto.KeyRing, name string, data []byte, maxAge time.Duration, secure bool) error {
+	value := base64.RawURLEncoding.EncodeToString(data)
+	if keys != nil {
+		sealed, err := keys.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		value = sealed
+	}
+
+	ck := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(ck)
+	ck.SetKey(name)
+	ck.SetValue(value)
+	ck.SetPath("/")
+	ck.SetHTTPOnly(true)
+	ck.SetSecure(secure)
+	ck.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+	ck.SetMaxAge(int(maxAge / time.Second))
+	c.Response.Header.SetCookie(ck)
+	return nil
+}
+
+func sealedCookie(c *context.Ctx, keys *fcrypto.KeyRing, name string) ([]byte, error) {
+	value := string(c.Request.Header.Cookie(name))
+	if value == "" {
+		return nil, ErrNoTokens
+	}
+	if keys != nil {
+		return keys.Decrypt(value)
+	}
+	return base64.RawURLEncoding.DecodeString(value)
+}
+
+func clearCookie(c *context.Ctx, name string) {
+	ck := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(ck)
+	ck.SetKey(name)
+	ck.SetPath("/")
+	ck.SetExpire(fasthttp.CookieExpireDelete)
+	c.Response.Header.SetCookie(ck)
+}
+
+func safeReturnTo(s string) string {
+	if !strings.HasPrefix(s, "/") || strings.HasPrefix(s, "//") || strings.HasPrefix(s, "/\\") {
+		return "/"
+	}
+	return s
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
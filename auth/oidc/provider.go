@@ -0,0 +1,240 @@
+package oidc
+
+import (
+	stdctx "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"fastrest/context"
+	"fastrest/pkg/clock"
+)
+
+const ClaimsKey context.Key[Claims] = "oidc.claims"
+
+type Config struct {
+	Issuer       string
+	Audience     string
+	JWKSURL      string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Leeway       time.Duration
+	CacheTTL     time.Duration
+	HTTPClient   *http.Client
+	Clock        clock.Clock
+	Store        TokenStore
+}
+
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+type Provider struct {
+	cfg  Config
+	keys *keySet
+
+	mu        sync.Mutex
+	discovery *Discovery
+}
+
+func New(cfg *Config) (*Provider, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("oidc: issuer is required")
+	}
+	c := *cfg
+	c.Issuer = strings.TrimSuffix(c.Issuer, "/")
+	if c.Audience == "" {
+		c.Audience = c.ClientID
+	}
+	if c.Audience == "" {
+		return nil, errors.New("oidc: audience or client id is required")
+	}
+	if c.Leeway == 0 {
+		c.Leeway = time.Minute
+	}
+	if c.CacheTTL == 0 {
+		c.CacheTTL = time.Hour
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if c.Clock == nil {
+		c.Clock = clock.System
+	}
+
+	return &Provider{
+		cfg: c,
+		keys: &keySet{
+			url:        c.JWKSURL,
+			client:     c.HTTPClient,
+			clock:      c.Clock,
+			ttl:        c.CacheTTL,
+			minRefresh: 30 * time.Second,
+		},
+	}, nil
+}
+
+func (p *Provider) Discover(ctx stdctx.Context) (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: discovery: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: discovery: unexpected status %d", resp.StatusCode)
+	}
+
+	var d Discovery
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, fmt.Errorf("oidc: decode discovery: %w", err)
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("oidc: discovery issuer %q does not match %q", d.Issuer, p.cfg.Issuer)
+	}
+	p.discovery = &d
+	return &d, nil
+}
+
+func (p *Provider) Verify(ctx stdctx.Context, token string) (Claims, error) {
+	return p.verify(ctx, token, p.cfg.Audience)
+}
+
+func (p *Provider) verify(ctx stdctx.Context, token, audience string) (Claims, error) {
+	t, err := parseJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	if !supportedAlg(t.header.Alg) {
+		return nil, ErrUnsupportedAlg
+	}
+
+	if err := p.resolveJWKS(ctx); err != nil {
+		return nil, err
+	}
+	key, err := p.keys.key(ctx, t.header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(t.header.Alg, key, t.signed, t.signature); err != nil {
+		return nil, err
+	}
+
+	if err := p.validate(t.claims, audience); err != nil {
+		return nil, err
+	}
+	return t.claims, nil
+}
+
+func (p *Provider) resolveJWKS(ctx stdctx.Context) error {
+	p.keys.mu.Lock()
+	known := p.keys.url != ""
+	p.keys.mu.Unlock()
+	if known {
+		return nil
+	}
+
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return err
+	}
+	p.keys.mu.Lock()
+	if p.keys.url == "" {
+		p.keys.url = d.JWKSURI
+	}
+	p.keys.mu.Unlock()
+	return nil
+}
+
+func (p *Provider) validate(claims Claims, audience string) error {
+	now := p.cfg.Clock.Now()
+	if strings.TrimSuffix(claims.Issuer(), "/") != p.cfg.Issuer {
+		return ErrInvalidIssuer
+	}
+	if !contains(claims.Audience(), audience) {
+		return ErrInvalidAudience
+	}
+	exp, ok := claims.Time("exp")
+	if !ok || now.After(exp.Add(p.cfg.Leeway)) {
+		return ErrTokenExpired
+	}
+	if nbf, ok := claims.Time("nbf"); ok && now.Add(p.cfg.Leeway).Before(nbf) {
+		return ErrTokenNotYetValid
+	}
+	if iat, ok := claims.Time("iat"); ok && now.Add(p.cfg.Leeway).Before(iat) {
+		return ErrTokenNotYetValid
+	}
+	return nil
+}
+
+func (p *Provider) Middleware() context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			auth := c.Get("Authorization")
+			if !strings.HasPrefix(auth, "Bearer ") {
+				c.Set("WWW-Authenticate", `Bearer realm="`+p.cfg.Issuer+`"`)
+				return c.Unauthorized("missing bearer token")
+			}
+
+			token := auth[7:]
+			claims, err := p.Verify(c.Context(), token)
+			if err != nil {
+				c.GetLogger().Debug("oidc token rejected", "error", err.Error())
+				c.Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				return c.Unauthorized("invalid token")
+			}
+
+			setAuth(c, "bearer", token, claims)
+			return next(c)
+		}
+	}
+}
+
+func FromCtx(c *context.Ctx) (Claims, bool) {
+	return ClaimsKey.Get(c)
+}
+
+func setAuth(c *context.Ctx, kind, token string, claims Claims) {
+	ClaimsKey.Set(c, claims)
+	exp, _ := claims.Time("exp")
+	c.SetAuth(&context.AuthInfo{
+		Type:      kind,
+		Value:     token,
+		Subject:   claims.Subject(),
+		Username:  claims.Subject(),
+		Scopes:    claims.Scopes(),
+		Roles:     claims.Strings("roles"),
+		Claims:    claims,
+		ExpiresAt: exp,
+		Valid:     true,
+	})
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
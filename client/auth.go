@@ -0,0 +1,82 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+
+	"fastrest/context"
+)
+
+type TokenMinter func(auth *context.AuthInfo) (string, error)
+
+type propagatedAuth struct {
+	headers map[string]string
+	hosts   []string
+}
+
+func WithAuthFrom(c *context.Ctx, allowedHosts ...string) Option {
+	return func(cl *Client) {
+		auth := c.GetAuth()
+		if auth == nil || !auth.Valid {
+			return
+		}
+
+		headers := make(map[string]string, 1)
+		switch auth.Type {
+		case "bearer":
+			headers["Authorization"] = "Bearer " + auth.Value
+		case "basic":
+			headers["Authorization"] = "Basic " + basicAuth(auth.Username, auth.Password)
+		case "apikey":
+			headers["X-API-Key"] = auth.Value
+		default:
+			return
+		}
+		cl.auth = &propagatedAuth{headers: headers, hosts: allowedHosts}
+	}
+}
+
+func WithMintedAuthFrom(c *context.Ctx, mint TokenMinter, allowedHosts ...string) Option {
+	return func(cl *Client) {
+		auth := c.GetAuth()
+		if auth == nil || !auth.Valid {
+			return
+		}
+
+		token, err := mint(auth)
+		if err != nil {
+			cl.err = err
+			return
+		}
+		cl.auth = &propagatedAuth{
+			headers: map[string]string{"Authorization": "Bearer " + token},
+			hosts:   allowedHosts,
+		}
+	}
+}
+
+func (p *propagatedAuth) allowed(target, baseURL string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	hosts := p.hosts
+	if len(hosts) == 0 {
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return false
+		}
+		hosts = []string{base.Host}
+	}
+
+	for _, h := range hosts {
+		if strings.EqualFold(h, u.Host) || strings.EqualFold(h, u.Hostname()) {
+			return true
+		}
+		if strings.HasPrefix(h, "*.") && strings.HasSuffix(strings.ToLower(u.Hostname()), strings.ToLower(h[1:])) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,17 +2,29 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	headers    map[string]string
+	baseURL      string
+	httpClient   *http.Client
+	headers      map[string]string
+	query        url.Values
+	timeout      time.Duration
+	retry        *retryPolicy
+	breaker      *CircuitBreaker
+	auth         *propagatedAuth
+	signer       *hmacSigner
+	interceptors []Interceptor
+	stats        *statsRegistry
+	err          error
 }
 
 type Option func(*Client)
@@ -29,7 +41,9 @@ func New(baseURL string, opts ...Option) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		headers: make(map[string]string),
+		headers: map[string]string{"User-Agent": DefaultUserAgent},
+		query:   make(url.Values),
+		stats:   &statsRegistry{},
 	}
 
 	for _, opt := range opts {
@@ -39,6 +53,33 @@ func New(baseURL string, opts ...Option) *Client {
 	return c
 }
 
+func (c *Client) with(opts []Option) *Client {
+	if len(opts) == 0 {
+		return c
+	}
+
+	clone := *c
+	httpClient := *c.httpClient
+	clone.httpClient = &httpClient
+	clone.headers = make(map[string]string, len(c.headers))
+	for k, v := range c.headers {
+		clone.headers[k] = v
+	}
+	clone.query = make(url.Values, len(c.query))
+	for k, v := range c.query {
+		clone.query[k] = append([]string{}, v...)
+	}
+	if c.retry != nil {
+		retry := *c.retry
+		clone.retry = &retry
+	}
+
+	for _, opt := range opts {
+		opt(&clone)
+	}
+	return &clone
+}
+
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Client) {
 		c.httpClient.Timeout = timeout
@@ -47,7 +88,19 @@ func WithTimeout(timeout time.Duration) Option {
 
 func WithHeader(key, value string) Option {
 	return func(c *Client) {
-		c.headers[key] = value
+		c.setHeader(key, value)
+	}
+}
+
+func WithQuery(key string, value interface{}) Option {
+	return func(c *Client) {
+		c.query.Add(key, fmt.Sprint(value))
+	}
+}
+
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
 	}
 }
 
@@ -69,7 +122,7 @@ func WithAPIKey(key string, headerName ...string) Option {
 		name = headerName[0]
 	}
 	return func(c *Client) {
-		c.headers[name] = key
+		c.setHeader(name, key)
 	}
 }
 
@@ -106,19 +159,86 @@ func base64Encode(data []byte) string {
 	return string(result)
 }
 
-func (c *Client) do(method, path string, body interface{}) (*Response, error) {
-	url := c.baseURL + path
+func (c *Client) do(ctx context.Context, method, path string, body interface{}) (*Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
 
-	var reqBody io.Reader
+	url := c.buildURL(path)
+
+	var data []byte
 	if body != nil {
-		data, err := json.Marshal(body)
+		var err error
+		data, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal body: %w", err)
 		}
+	}
+
+	healthy := false
+	if c.breaker != nil {
+		if !c.breaker.Allow() {
+			return nil, ErrCircuitOpen
+		}
+		defer func() { c.breaker.record(healthy) }()
+	}
+
+	attempts := 1
+	if c.retry != nil && c.canRetry(method) {
+		attempts += c.retry.max
+	}
+
+	var (
+		resp *Response
+		err  error
+	)
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(c.retry.delay(attempt, resp))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		resp, err = c.send(ctx, method, url, data, body != nil)
+		if attempt == attempts-1 || !c.retry.shouldRetry(resp, err) {
+			break
+		}
+	}
+
+	healthy = err == nil && resp.StatusCode < 500
+	return resp, err
+}
+
+func (c *Client) buildURL(path string) string {
+	u := c.baseURL + path
+	if len(c.query) == 0 {
+		return u
+	}
+	if strings.Contains(u, "?") {
+		return u + "&" + c.query.Encode()
+	}
+	return u + "?" + c.query.Encode()
+}
+
+func (c *Client) send(ctx context.Context, method, url string, data []byte, hasBody bool) (*Response, error) {
+	var reqBody io.Reader
+	if hasBody {
 		reqBody = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -127,11 +247,25 @@ func (c *Client) do(method, path string, body interface{}) (*Response, error) {
 		req.Header.Set(k, v)
 	}
 
-	if body != nil {
+	if c.auth != nil && c.auth.allowed(url, c.baseURL) {
+		for k, v := range c.auth.headers {
+			req.Header.Set(k, v)
+		}
+	}
+
+	if hasBody {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	resp, err := c.httpClient.Do(req)
+	if c.signer != nil {
+		c.signer.sign(req, data)
+	}
+
+	host := req.URL.Host
+	stats := c.stats.begin(host)
+	start := time.Now()
+	resp, err := c.roundTrip(req)
+	c.stats.end(host, stats, time.Since(start), err == nil && resp.StatusCode < 500)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -149,24 +283,24 @@ func (c *Client) do(method, path string, body interface{}) (*Response, error) {
 	}, nil
 }
 
-func (c *Client) Get(path string) (*Response, error) {
-	return c.do("GET", path, nil)
+func (c *Client) Get(ctx context.Context, path string, opts ...Option) (*Response, error) {
+	return c.with(opts).do(ctx, "GET", path, nil)
 }
 
-func (c *Client) Post(path string, body interface{}) (*Response, error) {
-	return c.do("POST", path, body)
+func (c *Client) Post(ctx context.Context, path string, body interface{}, opts ...Option) (*Response, error) {
+	return c.with(opts).do(ctx, "POST", path, body)
 }
 
-func (c *Client) Put(path string, body interface{}) (*Response, error) {
-	return c.do("PUT", path, body)
+func (c *Client) Put(ctx context.Context, path string, body interface{}, opts ...Option) (*Response, error) {
+	return c.with(opts).do(ctx, "PUT", path, body)
 }
 
-func (c *Client) Patch(path string, body interface{}) (*Response, error) {
-	return c.do("PATCH", path, body)
+func (c *Client) Patch(ctx context.Context, path string, body interface{}, opts ...Option) (*Response, error) {
+	return c.with(opts).do(ctx, "PATCH", path, body)
 }
 
-func (c *Client) Delete(path string) (*Response, error) {
-	return c.do("DELETE", path, nil)
+func (c *Client) Delete(ctx context.Context, path string, opts ...Option) (*Response, error) {
+	return c.with(opts).do(ctx, "DELETE", path, nil)
 }
 
 func (r *Response) JSON(v interface{}) error {
@@ -0,0 +1,43 @@
+package client
+
+import "net/http"
+
+const Version = "0.1.0"
+
+const DefaultUserAgent = "fastrest-client/" + Version
+
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.setHeader("User-Agent", ua)
+	}
+}
+
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		for k, v := range headers {
+			c.setHeader(k, v)
+		}
+	}
+}
+
+func WithoutHeader(key string) Option {
+	return func(c *Client) {
+		delete(c.headers, http.CanonicalHeaderKey(key))
+	}
+}
+
+func (c *Client) Header(key string) string {
+	return c.headers[http.CanonicalHeaderKey(key)]
+}
+
+func (c *Client) Headers() map[string]string {
+	headers := make(map[string]string, len(c.headers))
+	for k, v := range c.headers {
+		headers[k] = v
+	}
+	return headers
+}
+
+func (c *Client) setHeader(key, value string) {
+	c.headers[http.CanonicalHeaderKey(key)] = value
+}
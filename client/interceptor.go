@@ -0,0 +1,25 @@
+package client
+
+import "net/http"
+
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+type Interceptor func(req *http.Request, next RoundTripFunc) (*http.Response, error)
+
+func WithInterceptor(interceptors ...Interceptor) Option {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors[:len(c.interceptors):len(c.interceptors)], interceptors...)
+	}
+}
+
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	final := RoundTripFunc(c.httpClient.Do)
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor := c.interceptors[i]
+		next := final
+		final = func(req *http.Request) (*http.Response, error) {
+			return interceptor(req, next)
+		}
+	}
+	return final(req)
+}
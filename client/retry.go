@@ -0,0 +1,216 @@
+package client
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("client: circuit breaker is open")
+
+var defaultRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type retryPolicy struct {
+	max        int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	statuses   map[int]bool
+}
+
+func WithRetry(max int, backoff time.Duration) Option {
+	return func(c *Client) {
+		if backoff <= 0 {
+			backoff = 100 * time.Millisecond
+		}
+		if c.retry != nil {
+			c.retry.max = max
+			c.retry.backoff = backoff
+			return
+		}
+		statuses := make(map[int]bool, len(defaultRetryStatuses))
+		for _, code := range defaultRetryStatuses {
+			statuses[code] = true
+		}
+		c.retry = &retryPolicy{
+			max:        max,
+			backoff:    backoff,
+			maxBackoff: 30 * time.Second,
+			statuses:   statuses,
+		}
+	}
+}
+
+func WithRetryStatuses(codes ...int) Option {
+	return func(c *Client) {
+		if c.retry == nil {
+			WithRetry(3, 100*time.Millisecond)(c)
+		}
+		c.retry.statuses = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			c.retry.statuses[code] = true
+		}
+	}
+}
+
+func WithMaxBackoff(d time.Duration) Option {
+	return func(c *Client) {
+		if c.retry == nil {
+			WithRetry(3, 100*time.Millisecond)(c)
+		}
+		c.retry.maxBackoff = d
+	}
+}
+
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker = NewCircuitBreaker(threshold, cooldown)
+	}
+}
+
+func (c *Client) canRetry(method string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	default:
+		_, ok := c.headers["Idempotency-Key"]
+		return ok
+	}
+}
+
+func (p *retryPolicy) shouldRetry(resp *Response, err error) bool {
+	if p == nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return p.statuses[resp.StatusCode]
+}
+
+func (p *retryPolicy) delay(attempt int, last *Response) time.Duration {
+	if last != nil {
+		if d, ok := retryAfter(last.Headers.Get("Retry-After")); ok {
+			if d > p.maxBackoff {
+				return p.maxBackoff
+			}
+			return d
+		}
+	}
+
+	d := p.backoff << uint(attempt-1)
+	if d <= 0 || d > p.maxBackoff {
+		d = p.maxBackoff
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     breakerState
+	openedAt  time.Time
+	probing   bool
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+func (b *CircuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.failures = 0
+		b.state = breakerClosed
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"fastrest/crypto"
+)
+
+type hmacSigner struct {
+	keyID   string
+	secret  []byte
+	headers crypto.SignatureHeaders
+}
+
+func WithHMACSigning(keyID string, secret []byte) Option {
+	return WithHMACSigningHeaders(keyID, secret, crypto.DefaultSignatureHeaders())
+}
+
+func WithHMACSigningHeaders(keyID string, secret []byte, headers crypto.SignatureHeaders) Option {
+	return func(c *Client) {
+		c.signer = &hmacSigner{keyID: keyID, secret: secret, headers: headers.WithDefaults()}
+	}
+}
+
+func (s *hmacSigner) sign(req *http.Request, body []byte) {
+	timestamp := time.Now().Unix()
+	nonce := crypto.NewNonce()
+	canonical := crypto.CanonicalRequest(req.Method, req.URL.RequestURI(), timestamp, nonce, body)
+
+	req.Header.Set(s.headers.KeyID, s.keyID)
+	req.Header.Set(s.headers.Timestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(s.headers.Nonce, nonce)
+	req.Header.Set(s.headers.Signature, crypto.Sign(s.secret, canonical))
+}
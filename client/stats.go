@@ -0,0 +1,120 @@
+package client
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fastrest/metrics"
+)
+
+const latencyWindow = 256
+
+type HostStats struct {
+	Host        string  `json:"host"`
+	InFlight    int64   `json:"in_flight"`
+	Requests    int64   `json:"requests"`
+	Failures    int64   `json:"failures"`
+	SuccessRate float64 `json:"success_rate"`
+	P95Latency  float64 `json:"p95_latency_ms"`
+	Breaker     string  `json:"breaker,omitempty"`
+}
+
+type hostStats struct {
+	inFlight  int64
+	requests  int64
+	failures  int64
+	mu        sync.Mutex
+	latencies []time.Duration
+	next      int
+}
+
+type statsRegistry struct {
+	hosts   sync.Map
+	metrics *metrics.Metrics
+}
+
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(c *Client) {
+		c.stats.metrics = m
+	}
+}
+
+func (r *statsRegistry) host(host string) *hostStats {
+	val, ok := r.hosts.Load(host)
+	if !ok {
+		val, _ = r.hosts.LoadOrStore(host, &hostStats{latencies: make([]time.Duration, 0, latencyWindow)})
+	}
+	return val.(*hostStats)
+}
+
+func (r *statsRegistry) begin(host string) *hostStats {
+	s := r.host(host)
+	atomic.AddInt64(&s.inFlight, 1)
+	r.metrics.Gauge("client_requests_in_flight", "host", host).Inc()
+	return s
+}
+
+func (r *statsRegistry) end(host string, s *hostStats, duration time.Duration, success bool) {
+	atomic.AddInt64(&s.inFlight, -1)
+	atomic.AddInt64(&s.requests, 1)
+	outcome := "success"
+	if !success {
+		atomic.AddInt64(&s.failures, 1)
+		outcome = "failure"
+	}
+
+	s.mu.Lock()
+	if len(s.latencies) < latencyWindow {
+		s.latencies = append(s.latencies, duration)
+	} else {
+		s.latencies[s.next] = duration
+		s.next = (s.next + 1) % latencyWindow
+	}
+	s.mu.Unlock()
+
+	r.metrics.Gauge("client_requests_in_flight", "host", host).Dec()
+	r.metrics.Counter("client_requests_total", "host", host, "outcome", outcome).Inc()
+	r.metrics.Histogram("client_request_duration_seconds", "host", host).Observe(duration.Seconds())
+}
+
+func (s *hostStats) p95() time.Duration {
+	s.mu.Lock()
+	sorted := append([]time.Duration{}, s.latencies...)
+	s.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(len(sorted)*95-1)/100]
+}
+
+func (c *Client) Stats() []HostStats {
+	var stats []HostStats
+	c.stats.hosts.Range(func(key, value interface{}) bool {
+		s := value.(*hostStats)
+		requests := atomic.LoadInt64(&s.requests)
+		failures := atomic.LoadInt64(&s.failures)
+
+		hs := HostStats{
+			Host:        key.(string),
+			InFlight:    atomic.LoadInt64(&s.inFlight),
+			Requests:    requests,
+			Failures:    failures,
+			SuccessRate: 1,
+			P95Latency:  float64(s.p95()) / float64(time.Millisecond),
+		}
+		if requests > 0 {
+			hs.SuccessRate = float64(requests-failures) / float64(requests)
+		}
+		if c.breaker != nil {
+			hs.Breaker = c.breaker.State()
+		}
+		stats = append(stats, hs)
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Host < stats[j].Host })
+	return stats
+}
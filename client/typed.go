@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type APIError struct {
+	StatusCode int                    `json:"-"`
+	Message    string                 `json:"error"`
+	Details    map[string]interface{} `json:"-"`
+	Body       []byte                 `json:"-"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("client: status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("client: status %d", e.StatusCode)
+}
+
+func GetJSON[T any](ctx context.Context, c *Client, path string, opts ...Option) (T, *Response, error) {
+	return decodeJSON[T](c.Get(ctx, path, opts...))
+}
+
+func DeleteJSON[T any](ctx context.Context, c *Client, path string, opts ...Option) (T, *Response, error) {
+	return decodeJSON[T](c.Delete(ctx, path, opts...))
+}
+
+func PostJSON[Req, Resp any](ctx context.Context, c *Client, path string, body Req, opts ...Option) (Resp, *Response, error) {
+	return decodeJSON[Resp](c.Post(ctx, path, body, opts...))
+}
+
+func PutJSON[Req, Resp any](ctx context.Context, c *Client, path string, body Req, opts ...Option) (Resp, *Response, error) {
+	return decodeJSON[Resp](c.Put(ctx, path, body, opts...))
+}
+
+func PatchJSON[Req, Resp any](ctx context.Context, c *Client, path string, body Req, opts ...Option) (Resp, *Response, error) {
+	return decodeJSON[Resp](c.Patch(ctx, path, body, opts...))
+}
+
+func decodeJSON[T any](resp *Response, err error) (T, *Response, error) {
+	var out T
+	if err != nil {
+		return out, resp, err
+	}
+	if resp.IsError() {
+		return out, resp, newAPIError(resp)
+	}
+	if len(resp.Body) == 0 {
+		return out, resp, nil
+	}
+	if err := json.Unmarshal(resp.Body, &out); err != nil {
+		return out, resp, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return out, resp, nil
+}
+
+func newAPIError(resp *Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       resp.Body,
+	}
+	var details map[string]interface{}
+	if json.Unmarshal(resp.Body, &details) == nil {
+		apiErr.Details = details
+		if msg, ok := details["error"].(string); ok {
+			apiErr.Message = msg
+		} else if msg, ok := details["message"].(string); ok {
+			apiErr.Message = msg
+		}
+	}
+	if apiErr.Message == "" && len(resp.Body) > 0 && details == nil {
+		apiErr.Message = resp.String()
+	}
+	return apiErr
+}
@@ -0,0 +1,434 @@
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"fastrest/openapi"
+)
+
+type Options struct {
+	Package string
+	Module  string
+}
+
+type clientOperation struct {
+	name       string
+	summary    string
+	deprecated bool
+	method     string
+	path       string
+	params     []string
+	hasQuery   bool
+	bodyType   string
+	respType   string
+	clientFn   string
+	needsBody  bool
+}
+
+func WriteFile(doc *openapi.Document, path string, opts *Options) error {
+	src, err := Generate(doc, opts)
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, src, 0o644)
+}
+
+func Generate(doc *openapi.Document, opts *Options) ([]byte, error) {
+	o := Options{Package: "apiclient", Module: "fastrest"}
+	if opts != nil {
+		if opts.Package != "" {
+			o.Package = opts.Package
+		}
+		if opts.Module != "" {
+			o.Module = opts.Module
+		}
+	}
+	opts = &o
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by fastrest generate client. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", opts.Package)
+	fmt.Fprintf(&buf, "import (\n\t\"context\"\n\t\"fmt\"\n\t\"net/url\"\n\n\t%q\n)\n\n", opts.Module+"/client")
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeSchemaType(&buf, exportName(name), doc.Components.Schemas[name])
+	}
+
+	buf.WriteString(`type Client struct {
+	c *client.Client
+}
+
+func New(c *client.Client) *Client {
+	return &Client{c: c}
+}
+
+`)
+
+	for _, op := range collectOperations(doc) {
+		writeOperation(&buf, op)
+	}
+
+	buf.WriteString(`func decode(resp *client.Response, err error, v interface{}) error {
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, resp.String())
+	}
+	if v == nil || len(resp.Body) == 0 {
+		return nil
+	}
+	return resp.JSON(v)
+}
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+`)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated client: %w", err)
+	}
+	return src, nil
+}
+
+func collectOperations(doc *openapi.Document) []*clientOperation {
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var ops []*clientOperation
+	seen := make(map[string]int)
+	for _, p := range paths {
+		item := doc.Paths[p]
+		for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE"} {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			co := &clientOperation{
+				method:     method,
+				path:       p,
+				params:     pathParams(p),
+				summary:    op.Summary,
+				deprecated: op.Deprecated,
+			}
+
+			co.name = exportName(op.OperationID)
+			if co.name == "" {
+				co.name = operationName(method, p)
+			}
+			if n := seen[co.name]; n > 0 {
+				co.name = fmt.Sprintf("%s%d", co.name, n+1)
+			}
+			seen[co.name]++
+
+			for _, param := range append(append([]*openapi.Parameter{}, item.Parameters...), op.Parameters...) {
+				if param.In == "query" {
+					co.hasQuery = true
+				}
+			}
+
+			if op.RequestBody != nil {
+				if media, ok := op.RequestBody.Content["application/json"]; ok && media.Schema != nil {
+					co.bodyType = goType(media.Schema, true)
+				} else {
+					co.bodyType = "interface{}"
+				}
+			}
+			co.respType = responseType(op)
+
+			switch method {
+			case "GET":
+				co.clientFn = "Get"
+			case "DELETE":
+				co.clientFn = "Delete"
+			default:
+				co.clientFn = exportName(strings.ToLower(method))
+				co.needsBody = true
+			}
+			ops = append(ops, co)
+		}
+	}
+	return ops
+}
+
+func writeOperation(buf *bytes.Buffer, op *clientOperation) {
+	args := make([]string, 0, len(op.params)+3)
+	args = append(args, "ctx context.Context")
+	for _, p := range op.params {
+		args = append(args, lowerFirst(exportName(p))+" string")
+	}
+	if op.bodyType != "" && op.needsBody {
+		args = append(args, "body "+op.bodyType)
+	}
+	if op.hasQuery {
+		args = append(args, "query url.Values")
+	}
+
+	ret := "error"
+	if op.respType != "" {
+		ret = "(" + op.respType + ", error)"
+	}
+
+	if op.summary != "" {
+		fmt.Fprintf(buf, "// %s %s\n", op.name, summaryText(op.summary))
+	}
+	if op.deprecated {
+		if op.summary != "" {
+			buf.WriteString("//\n")
+		}
+		fmt.Fprintf(buf, "// Deprecated: %s %s is deprecated by the server.\n", op.method, op.path)
+	}
+	fmt.Fprintf(buf, "func (c *Client) %s(%s) %s {\n", op.name, strings.Join(args, ", "), ret)
+	fmt.Fprintf(buf, "\tpath := %s\n", pathExpr(op.path))
+	if op.hasQuery {
+		buf.WriteString("\tpath = withQuery(path, query)\n")
+	}
+
+	call := fmt.Sprintf("c.c.%s(ctx, path)", op.clientFn)
+	if op.needsBody {
+		body := "nil"
+		if op.bodyType != "" {
+			body = "body"
+		}
+		call = fmt.Sprintf("c.c.%s(ctx, path, %s)", op.clientFn, body)
+	}
+
+	if op.respType == "" {
+		fmt.Fprintf(buf, "\tresp, err := %s\n\treturn decode(resp, err, nil)\n}\n\n", call)
+		return
+	}
+
+	zero := "nil"
+	target := "&out"
+	if strings.HasPrefix(op.respType, "*") {
+		fmt.Fprintf(buf, "\tvar out %s\n", strings.TrimPrefix(op.respType, "*"))
+		fmt.Fprintf(buf, "\tresp, err := %s\n\tif err := decode(resp, err, %s); err != nil {\n\t\treturn %s, err\n\t}\n\treturn &out, nil\n}\n\n", call, target, zero)
+		return
+	}
+	fmt.Fprintf(buf, "\tvar out %s\n", op.respType)
+	fmt.Fprintf(buf, "\tresp, err := %s\n\tif err := decode(resp, err, %s); err != nil {\n\t\treturn out, err\n\t}\n\treturn out, nil\n}\n\n", call, target)
+}
+
+func responseType(op *openapi.Operation) string {
+	for _, code := range []string{"200", "201", "202", "2XX", "default"} {
+		resp, ok := op.Responses[code]
+		if !ok || resp == nil {
+			continue
+		}
+		media, ok := resp.Content["application/json"]
+		if !ok || media.Schema == nil {
+			return ""
+		}
+		t := goType(media.Schema, true)
+		if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "interface{}" {
+			return t
+		}
+		return "*" + strings.TrimPrefix(t, "*")
+	}
+	return ""
+}
+
+func writeSchemaType(buf *bytes.Buffer, name string, s *openapi.Schema) {
+	if s == nil {
+		return
+	}
+	if s.Type != "object" && len(s.Properties) == 0 {
+		fmt.Fprintf(buf, "type %s %s\n\n", name, goType(s, false))
+		return
+	}
+
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	props := make([]string, 0, len(s.Properties))
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, p := range props {
+		tag := p
+		if !required[p] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:%q`\n", fieldName(p), goType(s.Properties[p], false), tag)
+	}
+	buf.WriteString("}\n\n")
+}
+
+func goType(s *openapi.Schema, pointer bool) string {
+	if s == nil {
+		return "interface{}"
+	}
+	if s.Ref != "" {
+		name := exportName(s.Ref[strings.LastIndex(s.Ref, "/")+1:])
+		if pointer {
+			return "*" + name
+		}
+		return name
+	}
+	switch s.Type {
+	case "string":
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + goType(s.Items, false)
+	case "object":
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+}
+
+func pathParams(path string) []string {
+	var params []string
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			params = append(params, part[1:len(part)-1])
+		}
+	}
+	return params
+}
+
+func pathExpr(path string) string {
+	var parts []string
+	literal := ""
+	for i, seg := range strings.Split(path, "/") {
+		if i > 0 {
+			literal += "/"
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			parts = append(parts, fmt.Sprintf("%q", literal))
+			parts = append(parts, fmt.Sprintf("url.PathEscape(%s)", lowerFirst(exportName(seg[1:len(seg)-1]))))
+			literal = ""
+			continue
+		}
+		literal += seg
+	}
+	if literal != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+var initialisms = map[string]string{
+	"id":   "ID",
+	"ids":  "IDs",
+	"url":  "URL",
+	"uri":  "URI",
+	"api":  "API",
+	"http": "HTTP",
+	"json": "JSON",
+	"ip":   "IP",
+	"uuid": "UUID",
+}
+
+func fieldName(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == ' '
+	})
+	var b strings.Builder
+	for _, w := range words {
+		if i, ok := initialisms[strings.ToLower(w)]; ok {
+			b.WriteString(i)
+			continue
+		}
+		b.WriteString(exportName(w))
+	}
+	if b.Len() == 0 {
+		return exportName(s)
+	}
+	return b.String()
+}
+
+func operationName(method, path string) string {
+	var b strings.Builder
+	b.WriteString(exportName(strings.ToLower(method)))
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" {
+			continue
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			b.WriteString("By")
+			seg = seg[1 : len(seg)-1]
+		}
+		b.WriteString(fieldName(seg))
+	}
+	return b.String()
+}
+
+func summaryText(s string) string {
+	runes := []rune(strings.TrimSpace(s))
+	if len(runes) > 1 && unicode.IsUpper(runes[0]) && unicode.IsLower(runes[1]) {
+		runes[0] = unicode.ToLower(runes[0])
+	}
+	return strings.ReplaceAll(string(runes), "\n", " ")
+}
+
+func exportName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+type generateOptions struct {
+	Name   string
+	Dir    string
+	Module string
+	Force  bool
+}
+
+type resourceData struct {
+	Name    string
+	Var     string
+	Package string
+	Plural  string
+	Route   string
+	Module  string
+}
+
+func generateResource(opts *generateOptions) ([]string, error) {
+	name := exportName(opts.Name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid resource name %q", opts.Name)
+	}
+
+	plural := pluralize(name)
+	data := &resourceData{
+		Name:    name,
+		Var:     lowerFirst(name),
+		Package: strings.ToLower(name),
+		Plural:  plural,
+		Route:   "/" + kebab(plural),
+		Module:  opts.Module,
+	}
+
+	outDir := filepath.Join(opts.Dir, data.Package)
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		tmpl *template.Template
+	}{
+		{"dto.go", dtoTemplate},
+		{"handler.go", handlerTemplate},
+		{"routes.go", routesTemplate},
+		{"client.go", clientTemplate},
+	}
+
+	var created []string
+	for _, f := range files {
+		path := filepath.Join(outDir, f.name)
+		if !opts.Force {
+			if _, err := os.Stat(path); err == nil {
+				return created, fmt.Errorf("%s already exists (use -force to overwrite)", path)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := f.tmpl.Execute(&buf, data); err != nil {
+			return created, fmt.Errorf("render %s: %w", f.name, err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return created, fmt.Errorf("format %s: %w", f.name, err)
+		}
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			return created, err
+		}
+		created = append(created, path)
+	}
+	return created, nil
+}
+
+func exportName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+func pluralize(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return s + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
+
+func kebab(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"fastrest/clientgen"
+	"fastrest/openapi"
+)
+
+const usage = `Usage:
+  fastrest generate resource <Name> [flags]
+  fastrest generate client -spec <openapi.json|URL> [flags]
+
+"gen" is accepted as a short form of "generate".
+
+Flags:
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "fastrest:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) > 0 && args[0] == "gen" {
+		args = append([]string{"generate"}, args[1:]...)
+	}
+	if len(args) >= 2 && args[0] == "generate" && args[1] == "client" {
+		return runClient(args[2:])
+	}
+	if len(args) < 2 || args[0] != "generate" || args[1] != "resource" {
+		printUsage(newGenerateFlags(&generateOptions{}))
+		return fmt.Errorf("unknown command")
+	}
+
+	opts := &generateOptions{}
+	fs := newGenerateFlags(opts)
+	rest := args[2:]
+	if len(rest) == 0 || rest[0] == "" || rest[0][0] == '-' {
+		printUsage(fs)
+		return fmt.Errorf("resource name is required")
+	}
+	opts.Name = rest[0]
+	if err := fs.Parse(rest[1:]); err != nil {
+		return err
+	}
+
+	files, err := generateResource(opts)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		fmt.Println("created", f)
+	}
+	return nil
+}
+
+func runClient(args []string) error {
+	var spec, out string
+	opts := &clientgen.Options{}
+	fs := flag.NewFlagSet("generate client", flag.ContinueOnError)
+	fs.StringVar(&spec, "spec", "", "path or http(s) URL of an OpenAPI 3 document (JSON)")
+	fs.StringVar(&out, "out", "apiclient/client.go", "output file")
+	fs.StringVar(&opts.Package, "package", "apiclient", "package name of the generated client")
+	fs.StringVar(&opts.Module, "module", "fastrest", "import path of the fastrest module")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if spec == "" {
+		printUsage(fs)
+		return fmt.Errorf("-spec is required")
+	}
+
+	doc, err := loadSpec(spec)
+	if err != nil {
+		return err
+	}
+	if err := clientgen.WriteFile(doc, out, opts); err != nil {
+		return err
+	}
+	fmt.Println("created", out)
+	return nil
+}
+
+func loadSpec(spec string) (*openapi.Document, error) {
+	if !strings.HasPrefix(spec, "http://") && !strings.HasPrefix(spec, "https://") {
+		return openapi.Load(spec)
+	}
+	resp, err := http.Get(spec)
+	if err != nil {
+		return nil, fmt.Errorf("fetch spec: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch spec: unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetch spec: %w", err)
+	}
+	return openapi.Parse(data)
+}
+
+func newGenerateFlags(opts *generateOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("generate resource", flag.ContinueOnError)
+	fs.StringVar(&opts.Dir, "dir", ".", "output directory; files go into <dir>/<package>")
+	fs.StringVar(&opts.Module, "module", "fastrest", "import path of the fastrest module")
+	fs.BoolVar(&opts.Force, "force", false, "overwrite existing files")
+	return fs
+}
+
+func printUsage(fs *flag.FlagSet) {
+	fmt.Fprint(os.Stderr, usage)
+	fs.SetOutput(os.Stderr)
+	fs.PrintDefaults()
+}
@@ -0,0 +1,213 @@
+package main
+
+import "text/template"
+
+var dtoTemplate = template.Must(template.New("dto").Parse(`package {{.Package}}
+
+import "errors"
+
+type {{.Name}} struct {
+	ID   string ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Create{{.Name}}Request struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Update{{.Name}}Request struct {
+	Name *string ` + "`json:\"name,omitempty\"`" + `
+}
+
+func (r *Create{{.Name}}Request) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+func (r *Update{{.Name}}Request) Validate() error {
+	if r.Name != nil && *r.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	return nil
+}
+`))
+
+var handlerTemplate = template.Must(template.New("handler").Parse(`package {{.Package}}
+
+import (
+	"{{.Module}}"
+)
+
+type Store interface {
+	List() ([]*{{.Name}}, error)
+	Get(id string) (*{{.Name}}, error)
+	Create(req *Create{{.Name}}Request) (*{{.Name}}, error)
+	Update(id string, req *Update{{.Name}}Request) (*{{.Name}}, error)
+	Delete(id string) error
+}
+
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+func (h *Handler) List(c *fastrest.Ctx) error {
+	items, err := h.store.List()
+	if err != nil {
+		return err
+	}
+	return c.OK(items)
+}
+
+func (h *Handler) Get(c *fastrest.Ctx) error {
+	item, err := h.store.Get(c.Param("id"))
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return c.NotFound("{{.Var}} not found")
+	}
+	return c.OK(item)
+}
+
+func (h *Handler) Create(c *fastrest.Ctx) error {
+	var req Create{{.Name}}Request
+	if err := c.BodyParser(&req); err != nil {
+		return c.BadRequest("invalid JSON")
+	}
+	if err := req.Validate(); err != nil {
+		return c.BadRequest(err.Error())
+	}
+	item, err := h.store.Create(&req)
+	if err != nil {
+		return err
+	}
+	return c.Created(item)
+}
+
+func (h *Handler) Update(c *fastrest.Ctx) error {
+	var req Update{{.Name}}Request
+	if err := c.BodyParser(&req); err != nil {
+		return c.BadRequest("invalid JSON")
+	}
+	if err := req.Validate(); err != nil {
+		return c.BadRequest(err.Error())
+	}
+	item, err := h.store.Update(c.Param("id"), &req)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return c.NotFound("{{.Var}} not found")
+	}
+	return c.OK(item)
+}
+
+func (h *Handler) Delete(c *fastrest.Ctx) error {
+	if err := h.store.Delete(c.Param("id")); err != nil {
+		return err
+	}
+	return c.NoContent()
+}
+`))
+
+var routesTemplate = template.Must(template.New("routes").Parse(`package {{.Package}}
+
+import (
+	"{{.Module}}"
+)
+
+const BasePath = "{{.Route}}"
+
+func Register(app *fastrest.App, h *Handler) *fastrest.Router {
+	r := app.Group(BasePath)
+	r.GET("", h.List)
+	r.POST("", h.Create)
+	r.GET("/:id", h.Get)
+	r.PATCH("/:id", h.Update)
+	r.DELETE("/:id", h.Delete)
+	return r
+}
+`))
+
+var clientTemplate = template.Must(template.New("client").Parse(`package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"{{.Module}}/client"
+)
+
+type Client struct {
+	c *client.Client
+}
+
+func NewClient(c *client.Client) *Client {
+	return &Client{c: c}
+}
+
+func (c *Client) List(ctx context.Context) ([]*{{.Name}}, error) {
+	var items []*{{.Name}}
+	if err := c.do(c.c.Get(ctx, BasePath)).decode(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (c *Client) Get(ctx context.Context, id string) (*{{.Name}}, error) {
+	var item {{.Name}}
+	if err := c.do(c.c.Get(ctx, BasePath+"/"+url.PathEscape(id))).decode(&item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (c *Client) Create(ctx context.Context, req *Create{{.Name}}Request) (*{{.Name}}, error) {
+	var item {{.Name}}
+	if err := c.do(c.c.Post(ctx, BasePath, req)).decode(&item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (c *Client) Update(ctx context.Context, id string, req *Update{{.Name}}Request) (*{{.Name}}, error) {
+	var item {{.Name}}
+	if err := c.do(c.c.Patch(ctx, BasePath+"/"+url.PathEscape(id), req)).decode(&item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (c *Client) Delete(ctx context.Context, id string) error {
+	return c.do(c.c.Delete(ctx, BasePath+"/"+url.PathEscape(id))).decode(nil)
+}
+
+type result struct {
+	resp *client.Response
+	err  error
+}
+
+func (c *Client) do(resp *client.Response, err error) *result {
+	return &result{resp: resp, err: err}
+}
+
+func (r *result) decode(v interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	if !r.resp.IsSuccess() {
+		return fmt.Errorf("{{.Var}}: unexpected status %d: %s", r.resp.StatusCode, r.resp.String())
+	}
+	if v == nil || len(r.resp.Body) == 0 {
+		return nil
+	}
+	return r.resp.JSON(v)
+}
+`))
@@ -0,0 +1,253 @@
+package fastrest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const EnvPrefix = "FASTREST_"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func LoadConfig(files ...string) (*Config, error) {
+	cfg := &Config{}
+
+	if len(files) == 0 {
+		if file := os.Getenv(EnvPrefix + "CONFIG"); file != "" {
+			files = []string{file}
+		}
+	}
+
+	for _, file := range files {
+		values, err := readConfigFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyConfigValues(cfg, values, file); err != nil {
+			return nil, err
+		}
+	}
+
+	env := make(map[string]string)
+	for _, key := range configKeys() {
+		if v, ok := os.LookupEnv(EnvPrefix + strings.ToUpper(key)); ok {
+			env[key] = v
+		}
+	}
+	if err := applyConfigValues(cfg, env, "environment"); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if configurable(t.Field(i).Type) {
+			keys = append(keys, snakeCase(t.Field(i).Name))
+		}
+	}
+	return keys
+}
+
+func configurable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Uint64, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Float64 || t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+func applyConfigValues(cfg *Config, values map[string]string, source string) error {
+	rv := reflect.ValueOf(cfg).Elem()
+	t := rv.Type()
+
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if configurable(t.Field(i).Type) {
+			fields[snakeCase(t.Field(i).Name)] = i
+		}
+	}
+
+	for key, raw := range values {
+		i, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("config: %s: unknown key %q", source, key)
+		}
+		if err := setConfigField(rv.Field(i), strings.TrimSpace(raw)); err != nil {
+			return fmt.Errorf("config: %s: %s: %w", source, key, err)
+		}
+	}
+	return nil
+}
+
+func setConfigField(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		raw = strings.TrimSuffix(strings.TrimPrefix(raw, "["), "]")
+		list := reflect.MakeSlice(field.Type(), 0, 0)
+		for _, part := range strings.Split(raw, ",") {
+			if part = unquote(strings.TrimSpace(part)); part == "" {
+				continue
+			}
+			if field.Type().Elem().Kind() == reflect.String {
+				list = reflect.Append(list, reflect.ValueOf(part))
+				continue
+			}
+			f, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return err
+			}
+			list = reflect.Append(list, reflect.ValueOf(f))
+		}
+		field.Set(list)
+	}
+	return nil
+}
+
+func readConfigFile(file string) (map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return parseJSONConfig(data, file)
+	case ".yaml", ".yml":
+		return parseFlatConfig(data, file, ":")
+	case ".toml":
+		return parseFlatConfig(data, file, "=")
+	default:
+		return nil, fmt.Errorf("config: %s: unsupported format (use .json, .yaml, or .toml)", file)
+	}
+}
+
+func parseJSONConfig(data []byte, file string) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", file, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, msg := range raw {
+		var s string
+		var list []float64
+		var strs []string
+		switch {
+		case json.Unmarshal(msg, &s) == nil:
+			values[key] = s
+		case json.Unmarshal(msg, &list) == nil:
+			parts := make([]string, len(list))
+			for i, f := range list {
+				parts[i] = strconv.FormatFloat(f, 'g', -1, 64)
+			}
+			values[key] = strings.Join(parts, ",")
+		case json.Unmarshal(msg, &strs) == nil:
+			values[key] = strings.Join(strs, ",")
+		default:
+			values[key] = string(msg)
+		}
+	}
+	return values, nil
+}
+
+func parseFlatConfig(data []byte, file, sep string) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text == "---" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if strings.HasPrefix(text, "[") || unicode.IsSpace(rune(scanner.Text()[0])) {
+			return nil, fmt.Errorf("config: %s:%d: nested sections are not supported", file, line)
+		}
+
+		key, value, ok := strings.Cut(text, sep)
+		if !ok {
+			return nil, fmt.Errorf("config: %s:%d: expected key%svalue", file, line, sep)
+		}
+		values[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+	}
+	return values, scanner.Err()
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
+
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (prevLower || nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package fastrest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+const ConfigPath = "/_config"
+
+const maskedValue = "********"
+
+var secretKeyParts = []string{"secret", "password", "token", "credential", "private_key", "api_key", "dsn"}
+
+func (a *App) ConfigSnapshot() map[string]interface{} {
+	return snapshotStruct(reflect.ValueOf(a.config).Elem())
+}
+
+func (a *App) ConfigHandler() context.Handler {
+	return func(c *context.Ctx) error {
+		return c.RawJSON(constant.StatusOK, a.ConfigSnapshot())
+	}
+}
+
+func snapshotStruct(rv reflect.Value) map[string]interface{} {
+	t := rv.Type()
+	out := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := snakeCase(field.Name)
+		if isSecretKey(key) {
+			if !rv.Field(i).IsZero() {
+				out[key] = maskedValue
+			}
+			continue
+		}
+		out[key] = snapshotValue(rv.Field(i))
+	}
+	return out
+}
+
+func snapshotValue(v reflect.Value) interface{} {
+	if v.Type() == durationType {
+		return v.Interface().(fmt.Stringer).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Elem().Kind() == reflect.Struct {
+			return snapshotStruct(v.Elem())
+		}
+		return snapshotValue(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return fmt.Sprintf("%T", v.Interface())
+	case reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return nil
+		}
+		return v.Type().String()
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if isSecretKey(strings.ToLower(key)) {
+				out[key] = maskedValue
+			} else {
+				out[key] = snapshotValue(iter.Value())
+			}
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = snapshotValue(v.Index(i))
+		}
+		return out
+	case reflect.Struct:
+		return snapshotStruct(v)
+	default:
+		return v.Interface()
+	}
+}
+
+func isSecretKey(key string) bool {
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
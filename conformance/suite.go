@@ -0,0 +1,217 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Check struct {
+	Name     string
+	Category string
+	Method   string
+	Path     string
+	Headers  map[string]string
+	Body     interface{}
+	RawBody  string
+	Expect   []int
+	Capture  string
+	Validate func(resp *http.Response, body []byte) error
+}
+
+type Result struct {
+	Name     string        `json:"name"`
+	Category string        `json:"category"`
+	Method   string        `json:"method"`
+	Path     string        `json:"path"`
+	Passed   bool          `json:"passed"`
+	Status   int           `json:"status,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+type Report struct {
+	Results  []Result      `json:"results"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+func (r *Report) OK() bool {
+	return r.Failed == 0
+}
+
+func (r *Report) Failures() []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if !res.Passed {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+func (r *Report) WriteText(w io.Writer) {
+	for _, res := range r.Results {
+		mark := "PASS"
+		if !res.Passed {
+			mark = "FAIL"
+		}
+		fmt.Fprintf(w, "[%s] %-8s %s %s %s (%d, %s)", mark, res.Category, res.Name, res.Method, res.Path, res.Status, res.Duration.Round(time.Microsecond))
+		if res.Error != "" {
+			fmt.Fprintf(w, ": %s", res.Error)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d passed, %d failed in %s\n", r.Passed, r.Failed, r.Duration.Round(time.Microsecond))
+}
+
+type Suite struct {
+	checks []Check
+}
+
+func New(checks ...Check) *Suite {
+	return &Suite{checks: checks}
+}
+
+func (s *Suite) Add(checks ...Check) *Suite {
+	s.checks = append(s.checks, checks...)
+	return s
+}
+
+func (s *Suite) Run(target Target) *Report {
+	report := &Report{}
+	vars := make(map[string]string)
+	began := time.Now()
+
+	for _, check := range s.checks {
+		res := runCheck(target, check, vars)
+		if res.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	report.Duration = time.Since(began)
+	return report
+}
+
+func runCheck(target Target, check Check, vars map[string]string) Result {
+	path := expand(check.Path, vars)
+	res := Result{Name: check.Name, Category: check.Category, Method: check.Method, Path: path}
+	start := time.Now()
+
+	fail := func(format string, args ...interface{}) Result {
+		res.Error = fmt.Sprintf(format, args...)
+		res.Duration = time.Since(start)
+		return res
+	}
+
+	if strings.Contains(path, "{") {
+		return fail("unresolved variable in path (did an earlier check fail to capture it?)")
+	}
+
+	var body io.Reader
+	switch {
+	case check.RawBody != "":
+		body = strings.NewReader(check.RawBody)
+	case check.Body != nil:
+		data, err := json.Marshal(check.Body)
+		if err != nil {
+			return fail("encode body: %v", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(check.Method, path, body)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range check.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := target.Do(req)
+	if err != nil {
+		return fail("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fail("read body: %v", err)
+	}
+	res.Status = resp.StatusCode
+
+	if len(check.Expect) > 0 && !containsStatus(check.Expect, resp.StatusCode) {
+		return fail("expected status %v, got %d: %s", check.Expect, resp.StatusCode, truncate(data))
+	}
+	if check.Capture != "" {
+		value, err := captureField(data, check.Capture)
+		if err != nil {
+			return fail("capture %q: %v", check.Capture, err)
+		}
+		vars[check.Capture] = value
+	}
+	if check.Validate != nil {
+		if err := check.Validate(resp, data); err != nil {
+			return fail("%v", err)
+		}
+	}
+
+	res.Passed = true
+	res.Duration = time.Since(start)
+	return res
+}
+
+func expand(path string, vars map[string]string) string {
+	for k, v := range vars {
+		path = strings.ReplaceAll(path, "{"+k+"}", v)
+	}
+	return path
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func captureField(data []byte, field string) (string, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return "", fmt.Errorf("response is not a JSON object")
+	}
+	if inner, ok := obj["data"].(map[string]interface{}); ok && obj[field] == nil {
+		obj = inner
+	}
+	value, ok := obj[field]
+	if !ok || value == nil {
+		return "", fmt.Errorf("field not present in response")
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+func truncate(data []byte) string {
+	if len(data) > 200 {
+		return string(data[:200]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,49 @@
+package conformance
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"fastrest"
+)
+
+type Target interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type appTarget struct {
+	app     *fastrest.App
+	timeout time.Duration
+}
+
+func App(app *fastrest.App) Target {
+	return &appTarget{app: app, timeout: 5 * time.Second}
+}
+
+func (t *appTarget) Do(req *http.Request) (*http.Response, error) {
+	return t.app.Test(req, t.timeout)
+}
+
+type urlTarget struct {
+	base   string
+	client *http.Client
+}
+
+func URL(baseURL string) Target {
+	return &urlTarget{
+		base:   strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (t *urlTarget) Do(req *http.Request) (*http.Response, error) {
+	u, err := req.URL.Parse(t.base + req.URL.RequestURI())
+	if err != nil {
+		return nil, err
+	}
+	req.URL = u
+	req.Host = u.Host
+	req.RequestURI = ""
+	return t.client.Do(req)
+}
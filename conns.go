@@ -0,0 +1,99 @@
+package fastrest
+
+import (
+	"net"
+	"sync"
+
+	"fastrest/metrics"
+)
+
+type ConnStats struct {
+	Open     int64 `json:"open"`
+	New      int64 `json:"new"`
+	Active   int64 `json:"active"`
+	Idle     int64 `json:"idle"`
+	Hijacked int64 `json:"hijacked_total"`
+	Accepted int64 `json:"accepted_total"`
+}
+
+type connTracker struct {
+	mu       sync.Mutex
+	states   map[net.Conn]string
+	stats    ConnStats
+	metrics  *metrics.Metrics
+	recorder metrics.Recorder
+}
+
+func newConnTracker(m *metrics.Metrics, recorder metrics.Recorder) *connTracker {
+	return &connTracker{
+		states:   make(map[net.Conn]string),
+		metrics:  m,
+		recorder: recorder,
+	}
+}
+
+func (t *connTracker) transition(conn net.Conn, state string) {
+	t.mu.Lock()
+	prev, known := t.states[conn]
+	if known {
+		t.adjust(prev, -1)
+	}
+	switch state {
+	case "hijacked", "closed":
+		delete(t.states, conn)
+	default:
+		t.states[conn] = state
+		t.adjust(state, 1)
+	}
+
+	opened := !known && state == "new"
+	closed := known && (state == "hijacked" || state == "closed")
+	if opened {
+		t.stats.Open++
+		t.stats.Accepted++
+	}
+	if closed {
+		t.stats.Open--
+	}
+	if state == "hijacked" {
+		t.stats.Hijacked++
+	}
+	t.mu.Unlock()
+
+	if state == "hijacked" {
+		t.metrics.Counter("http_connections_hijacked_total").Inc()
+	}
+	if t.recorder == nil {
+		return
+	}
+	if opened {
+		t.recorder.IncActiveConns()
+	}
+	if closed {
+		t.recorder.DecActiveConns()
+	}
+}
+
+func (t *connTracker) adjust(state string, delta int64) {
+	switch state {
+	case "new":
+		t.stats.New += delta
+	case "active":
+		t.stats.Active += delta
+	case "idle":
+		t.stats.Idle += delta
+	default:
+		return
+	}
+	t.metrics.Gauge("http_connections", "state", state).Add(float64(delta))
+}
+
+func (t *connTracker) snapshot() ConnStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+func (a *App) Connections() ConnStats {
+	return a.conns.snapshot()
+}
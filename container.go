@@ -0,0 +1,119 @@
+package fastrest
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"fastrest/context"
+)
+
+const scopedLocalPrefix = "di:"
+
+type provider struct {
+	singleton func() (interface{}, error)
+	scoped    func(c *context.Ctx) (interface{}, error)
+	mu        sync.Mutex
+	built     bool
+	value     interface{}
+}
+
+type container struct {
+	mu        sync.RWMutex
+	providers map[string]*provider
+	order     []string
+}
+
+func newContainer() *container {
+	return &container{providers: make(map[string]*provider)}
+}
+
+func (ct *container) register(key string, p *provider) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if _, exists := ct.providers[key]; !exists {
+		ct.order = append(ct.order, key)
+	}
+	ct.providers[key] = p
+}
+
+func (ct *container) Resolve(c *context.Ctx, key string) (interface{}, error) {
+	ct.mu.RLock()
+	p, ok := ct.providers[key]
+	ct.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w for %q", context.ErrNoProvider, key)
+	}
+
+	if p.scoped != nil {
+		local := scopedLocalPrefix + key
+		if v, ok := c.Locals[local]; ok {
+			return v, nil
+		}
+		v, err := p.scoped(c)
+		if err != nil {
+			return nil, fmt.Errorf("fastrest: provide %q: %w", key, err)
+		}
+		c.Locals[local] = v
+		return v, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.built {
+		v, err := p.singleton()
+		if err != nil {
+			return nil, fmt.Errorf("fastrest: provide %q: %w", key, err)
+		}
+		p.value, p.built = v, true
+	}
+	return p.value, nil
+}
+
+func (ct *container) close() []error {
+	ct.mu.RLock()
+	defer ct.mu.RUnlock()
+
+	var errs []error
+	for i := len(ct.order) - 1; i >= 0; i-- {
+		p := ct.providers[ct.order[i]]
+		p.mu.Lock()
+		closer, ok := p.value.(io.Closer)
+		if p.built && ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", ct.order[i], err))
+			}
+		}
+		p.built, p.value = false, nil
+		p.mu.Unlock()
+	}
+	return errs
+}
+
+func (a *App) Provide(key string, constructor func() (interface{}, error)) {
+	a.container.register(key, &provider{singleton: constructor})
+}
+
+func (a *App) ProvideScoped(key string, constructor func(c *Ctx) (interface{}, error)) {
+	a.container.register(key, &provider{scoped: constructor})
+}
+
+func Provide[T any](a *App, constructor func() (T, error)) {
+	a.Provide(context.TypeKey[T](), func() (interface{}, error) {
+		return constructor()
+	})
+}
+
+func ProvideScoped[T any](a *App, constructor func(c *Ctx) (T, error)) {
+	a.ProvideScoped(context.TypeKey[T](), func(c *Ctx) (interface{}, error) {
+		return constructor(c)
+	})
+}
+
+func Inject[T any](c *Ctx) (T, error) {
+	return context.Inject[T](c)
+}
+
+func ResolveAs[T any](c *Ctx, key string) (T, error) {
+	return context.ResolveAs[T](c, key)
+}
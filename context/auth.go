@@ -0,0 +1,60 @@
+package context
+
+import (
+	"time"
+
+	"fastrest/constant"
+)
+
+type authError struct{}
+
+func (authError) Error() string {
+	return "authentication required"
+}
+
+func (authError) StatusCode() int {
+	return constant.StatusUnauthorized
+}
+
+var ErrNoAuth error = authError{}
+
+func (c *Ctx) MustAuth() (*AuthInfo, error) {
+	if c.Auth == nil || !c.Auth.Valid || c.Auth.Expired(c.Now()) {
+		return nil, ErrNoAuth
+	}
+	return c.Auth, nil
+}
+
+func (a *AuthInfo) Expired(now time.Time) bool {
+	return a != nil && !a.ExpiresAt.IsZero() && !now.Before(a.ExpiresAt)
+}
+
+func (a *AuthInfo) HasRole(role string) bool {
+	if a == nil {
+		return false
+	}
+	for _, r := range a.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *AuthInfo) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		if a.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+func Claim[T any](a *AuthInfo, name string) (T, bool) {
+	var zero T
+	if a == nil {
+		return zero, false
+	}
+	v, ok := a.Claims[name].(T)
+	return v, ok
+}
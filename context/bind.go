@@ -0,0 +1,243 @@
+package context
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"fastrest/constant"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+type BindError struct {
+	Source string
+	Field  string
+	Value  string
+	Offset int64
+	Err    error
+}
+
+func (e *BindError) Error() string {
+	switch {
+	case e.Field != "":
+		return fmt.Sprintf("invalid %s %q: %v", e.Source, e.Field, e.Err)
+	case e.Offset > 0:
+		return fmt.Sprintf("invalid %s at offset %d: %v", e.Source, e.Offset, e.Err)
+	}
+	return fmt.Sprintf("invalid %s: %v", e.Source, e.Err)
+}
+
+func (c *Ctx) sendBindError(err error) {
+	if errors.Is(err, ErrBodyTooLarge) {
+		c.PayloadTooLarge(err.Error())
+		return
+	}
+	var be *BindError
+	if errors.As(err, &be) && (be.Field != "" || be.Offset > 0) {
+		details := map[string]interface{}{"source": be.Source}
+		if be.Field != "" {
+			details["field"] = be.Field
+		}
+		if be.Offset > 0 {
+			details["offset"] = be.Offset
+		}
+		c.SendErrorDetails(constant.StatusBadRequest, err.Error(), details)
+		return
+	}
+	c.BadRequest(err.Error())
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+func (c *Ctx) Bind(v interface{}) error {
+	err := c.bind(v)
+	if err != nil {
+		c.sendBindError(err)
+	}
+	return err
+}
+
+func (c *Ctx) bind(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return &BindError{Source: "target", Err: errors.New("Bind requires a non-nil pointer to a struct")}
+	}
+
+	if body := c.Body(); len(body) > 0 {
+		o := c.decodeOptions(nil)
+		if o.MaxBodySize > 0 && len(body) > o.MaxBodySize {
+			return ErrBodyTooLarge
+		}
+		if mediaType := c.formMediaType(); mediaType != "" {
+			if err := c.parseForm(rv.Elem(), mediaType); err != nil {
+				return err
+			}
+		} else if err := decodeJSON(body, v, o); err != nil {
+			return err
+		}
+	}
+	return c.bindFields(rv.Elem())
+}
+
+func (c *Ctx) formMediaType() string {
+	ct := string(c.Request.Header.ContentType())
+	switch {
+	case strings.HasPrefix(ct, "application/x-www-form-urlencoded"):
+		return "application/x-www-form-urlencoded"
+	case strings.HasPrefix(ct, "multipart/form-data"):
+		return "multipart/form-data"
+	}
+	return ""
+}
+
+func (c *Ctx) bindFields(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		field := v.Field(i)
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if err := c.bindFields(field); err != nil {
+				return err
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		for _, source := range []string{"param", "query", "header"} {
+			name := sf.Tag.Get(source)
+			if name == "" || name == "-" {
+				continue
+			}
+			values := c.bindValues(source, name)
+			if len(values) == 0 {
+				continue
+			}
+			if err := setBindValue(field, values, sf.Tag.Get("layout"), true); err != nil {
+				return &BindError{Source: source, Field: name, Value: strings.Join(values, ","), Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Ctx) bindValues(source, name string) []string {
+	switch source {
+	case "param":
+		if v, ok := c.Params[name]; ok {
+			return []string{v}
+		}
+	case "query":
+		var values []string
+		for _, v := range c.QueryArgs().PeekMulti(name) {
+			values = append(values, string(v))
+		}
+		return values
+	case "header":
+		if v := c.Request.Header.Peek(name); v != nil {
+			return []string{string(v)}
+		}
+	}
+	return nil
+}
+
+func setBindValue(field reflect.Value, values []string, layout string, split bool) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		if split && len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, raw := range values {
+			if err := setBindScalar(slice.Index(i), strings.TrimSpace(raw), layout); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setBindScalar(field, values[0], layout)
+}
+
+func setBindScalar(field reflect.Value, raw, layout string) error {
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setBindScalar(ptr.Elem(), raw, layout); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+
+	switch field.Type() {
+	case durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.New("expected duration")
+		}
+		field.SetInt(int64(d))
+		return nil
+	case timeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, raw)
+		if err != nil {
+			return fmt.Errorf("expected time in layout %q", layout)
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return expected(field)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return expected(field)
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return expected(field)
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return expected(field)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+func expected(field reflect.Value) error {
+	return fmt.Errorf("expected %s", field.Type())
+}
@@ -0,0 +1,80 @@
+package context
+
+import (
+	"errors"
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"strings"
+
+	"fastrest/constant"
+)
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+type MediaTypeError struct {
+	ContentType string
+}
+
+func (e *MediaTypeError) Error() string {
+	return "unsupported content type " + e.ContentType
+}
+
+func (e *MediaTypeError) StatusCode() int {
+	return constant.StatusUnsupportedMediaType
+}
+
+func (c *Ctx) BodyParser(v interface{}, opts ...BodyOption) error {
+	ct := string(c.Request.Header.ContentType())
+	mediaType, _, _ := mime.ParseMediaType(ct)
+
+	o := c.decodeOptions(opts)
+	var err error
+	switch {
+	case o.MaxBodySize > 0 && len(c.Body()) > o.MaxBodySize:
+		err = ErrBodyTooLarge
+	case mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		err = decodeJSON(c.Body(), v, o)
+	case mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data":
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return &BindError{Source: "target", Err: errors.New("BodyParser requires a non-nil pointer to a struct for form bodies")}
+		}
+		err = c.parseForm(rv.Elem(), mediaType)
+	default:
+		return &MediaTypeError{ContentType: ct}
+	}
+	if err != nil {
+		c.sendBindError(err)
+	}
+	return err
+}
+
+func (c *Ctx) parseForm(v reflect.Value, mediaType string) error {
+	src := valueSource{tag: "form", values: make(map[string][]string)}
+
+	if mediaType == "multipart/form-data" {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return &BindError{Source: "body", Err: err}
+		}
+		for k, vals := range form.Value {
+			key := strings.TrimSuffix(k, "[]")
+			src.values[key] = append(src.values[key], vals...)
+		}
+		src.files = make(map[string][]*multipart.FileHeader, len(form.File))
+		for k, files := range form.File {
+			key := strings.TrimSuffix(k, "[]")
+			src.files[key] = append(src.files[key], files...)
+		}
+	} else {
+		for k, val := range c.PostArgs().All() {
+			key := strings.TrimSuffix(string(k), "[]")
+			src.values[key] = append(src.values[key], string(val))
+		}
+	}
+	return parseValues(v, src, "")
+}
@@ -0,0 +1,6 @@
+package context
+
+func (c *Ctx) CacheTags(tags ...string) {
+	existing, _ := CacheTagsKey.Get(c)
+	CacheTagsKey.Set(c, append(existing, tags...))
+}
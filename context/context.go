@@ -1,6 +1,7 @@
 package context
 
 import (
+	stdctx "context"
 	"encoding/json"
 	"strconv"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"github.com/valyala/fasthttp"
 
 	"fastrest/constant"
+	"fastrest/pkg/clock"
 	"fastrest/pkg/logging"
 )
 
@@ -22,14 +24,39 @@ type Ctx struct {
 	Locals map[string]interface{}
 	Logger logging.Logger
 	Auth   *AuthInfo
+
+	requestID string
+	timedOut  bool
+	assets    AssetResolver
+	views     Renderer
+	clock     clock.Clock
+	ctx       stdctx.Context
+	proxies   *TrustedProxies
+	problems  bool
+	locale    Translator
+	resolver  Resolver
+	decode    *DecodeOptions
+	wrapper   ResponseWrapper
+	features  FeatureChecker
+}
+
+type ResponseWrapper func(c *Ctx, payload interface{}) interface{}
+
+type AssetResolver interface {
+	Path(name string) string
 }
 
 type AuthInfo struct {
-	Type     string
-	Value    string
-	Username string
-	Password string
-	Valid    bool
+	Type      string
+	Value     string
+	Subject   string
+	Username  string
+	Password  string
+	Scopes    []string
+	Roles     []string
+	Claims    map[string]interface{}
+	ExpiresAt time.Time
+	Valid     bool
 }
 
 func (c *Ctx) Param(key string) string {
@@ -157,15 +184,23 @@ func (c *Ctx) QueryIntSlice(key, separator string) ([]int, error) {
 	return result, nil
 }
 
+func (c *Ctx) IncludeDeleted() bool {
+	return c.QueryBoolDefault("include_deleted", false)
+}
+
 func (c *Ctx) Body() []byte {
 	return c.Request.Body()
 }
 
-func (c *Ctx) BodyParser(v interface{}) error {
-	return json.Unmarshal(c.Body(), v)
+func (c *Ctx) JSON(status int, v interface{}) error {
+	c.Response.SetStatusCode(status)
+	if c.wrapper != nil {
+		v = c.wrapper(c, v)
+	}
+	return c.RawJSON(status, v)
 }
 
-func (c *Ctx) JSON(status int, v interface{}) error {
+func (c *Ctx) RawJSON(status int, v interface{}) error {
 	c.Response.Header.SetContentType("application/json")
 	c.Response.SetStatusCode(status)
 	data, err := json.Marshal(v)
@@ -216,16 +251,64 @@ func (c *Ctx) Path() string {
 	return string(c.URI().Path())
 }
 
-func (c *Ctx) IP() string {
-	return c.RemoteIP().String()
-}
-
 func (c *Ctx) GetAuth() *AuthInfo {
 	return c.Auth
 }
 
 func (c *Ctx) SetAuth(auth *AuthInfo) {
 	c.Auth = auth
+	if auth == nil {
+		AuthKey.Delete(c)
+	} else {
+		AuthKey.Set(c, auth)
+	}
+}
+
+func (c *Ctx) RequestID() string {
+	return c.requestID
+}
+
+func (c *Ctx) SetRequestID(id string) {
+	c.requestID = id
+	if id == "" {
+		RequestIDKey.Delete(c)
+	} else {
+		RequestIDKey.Set(c, id)
+	}
+}
+
+func (c *Ctx) TimedOut() bool {
+	return c.timedOut
+}
+
+func (c *Ctx) SetTimedOut(timedOut bool) {
+	c.timedOut = timedOut
+}
+
+func (c *Ctx) Clock() clock.Clock {
+	if c.clock == nil {
+		return clock.System
+	}
+	return c.clock
+}
+
+func (c *Ctx) SetClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+func (c *Ctx) Now() time.Time {
+	return c.Clock().Now()
+}
+
+func (c *Ctx) SetAssets(assets AssetResolver) {
+	c.assets = assets
+}
+
+func (c *Ctx) AssetPath(name string) string {
+	if c.assets == nil {
+		return name
+	}
+	return c.assets.Path(name)
 }
 
 func (c *Ctx) Redirect(url string, status int) error {
@@ -235,8 +318,7 @@ func (c *Ctx) Redirect(url string, status int) error {
 }
 
 func (c *Ctx) SendFile(filepath string) error {
-	c.Response.SendFile(filepath)
-	return nil
+	return c.SendFileRange(filepath)
 }
 
 func (c *Ctx) NoContent() error {
@@ -253,21 +335,25 @@ func (c *Ctx) OK(v interface{}) error {
 }
 
 func (c *Ctx) BadRequest(msg string) error {
-	return c.JSON(constant.StatusBadRequest, map[string]string{"error": msg})
+	return c.SendError(constant.StatusBadRequest, msg)
 }
 
 func (c *Ctx) Unauthorized(msg string) error {
-	return c.JSON(constant.StatusUnauthorized, map[string]string{"error": msg})
+	return c.SendError(constant.StatusUnauthorized, msg)
 }
 
 func (c *Ctx) Forbidden(msg string) error {
-	return c.JSON(constant.StatusForbidden, map[string]string{"error": msg})
+	return c.SendError(constant.StatusForbidden, msg)
 }
 
 func (c *Ctx) NotFound(msg string) error {
-	return c.JSON(constant.StatusNotFound, map[string]string{"error": msg})
+	return c.SendError(constant.StatusNotFound, msg)
+}
+
+func (c *Ctx) PayloadTooLarge(msg string) error {
+	return c.SendError(constant.StatusRequestEntityTooLarge, msg)
 }
 
 func (c *Ctx) InternalServerError(msg string) error {
-	return c.JSON(constant.StatusInternalServerError, map[string]string{"error": msg})
+	return c.SendError(constant.StatusInternalServerError, msg)
 }
@@ -0,0 +1,134 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type DecodeOptions struct {
+	DisallowUnknownFields bool
+	UseNumber             bool
+	MaxDepth              int
+	MaxBodySize           int
+}
+
+type BodyOption func(*DecodeOptions)
+
+func DisallowUnknownFields() BodyOption {
+	return func(o *DecodeOptions) {
+		o.DisallowUnknownFields = true
+	}
+}
+
+func UseNumber() BodyOption {
+	return func(o *DecodeOptions) {
+		o.UseNumber = true
+	}
+}
+
+func MaxDepth(depth int) BodyOption {
+	return func(o *DecodeOptions) {
+		o.MaxDepth = depth
+	}
+}
+
+func MaxBodySize(size int) BodyOption {
+	return func(o *DecodeOptions) {
+		o.MaxBodySize = size
+	}
+}
+
+var ErrBodyTooLarge = errors.New("request body too large")
+
+func (c *Ctx) SetDecodeOptions(opts *DecodeOptions) {
+	c.decode = opts
+}
+
+func (c *Ctx) decodeOptions(opts []BodyOption) DecodeOptions {
+	var o DecodeOptions
+	if c.decode != nil {
+		o = *c.decode
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func decodeJSON(body []byte, v interface{}, o DecodeOptions) error {
+	if o.MaxDepth > 0 {
+		if offset := exceedsDepth(body, o.MaxDepth); offset >= 0 {
+			return &BindError{Source: "body", Offset: offset, Err: fmt.Errorf("exceeds maximum nesting depth of %d", o.MaxDepth)}
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if o.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if o.UseNumber {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(v); err != nil {
+		return jsonBindError(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return &BindError{Source: "body", Offset: dec.InputOffset(), Err: errors.New("unexpected data after JSON value")}
+	}
+	return nil
+}
+
+func jsonBindError(err error) *BindError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &BindError{Source: "body", Offset: syntaxErr.Offset, Err: err}
+	case errors.As(err, &typeErr):
+		return &BindError{
+			Source: "body",
+			Field:  typeErr.Field,
+			Value:  typeErr.Value,
+			Offset: typeErr.Offset,
+			Err:    fmt.Errorf("expected %s", typeErr.Type),
+		}
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return &BindError{Source: "body", Err: io.ErrUnexpectedEOF}
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return &BindError{Source: "body", Field: strings.Trim(field, `"`), Err: errors.New("unknown field")}
+	}
+	return &BindError{Source: "body", Err: err}
+}
+
+func exceedsDepth(body []byte, max int) int64 {
+	depth := 0
+	inString, escaped := false, false
+	for i, b := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch b {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case b == '"':
+			inString = true
+		case b == '{' || b == '[':
+			depth++
+			if depth > max {
+				return int64(i)
+			}
+		case b == '}' || b == ']':
+			depth--
+		}
+	}
+	return -1
+}
@@ -0,0 +1,63 @@
+package context
+
+import (
+	stdctx "context"
+
+	"github.com/valyala/fasthttp"
+)
+
+func (c *Ctx) Context() stdctx.Context {
+	if c.ctx == nil {
+		return stdctx.Background()
+	}
+	return c.ctx
+}
+
+func (c *Ctx) SetContext(ctx stdctx.Context) {
+	c.ctx = ctx
+}
+
+func (c *Ctx) Detach() *Ctx {
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Init(&c.Request, c.RemoteAddr(), nil)
+	SetEarlyHints(fctx, func(links []string) error { return nil })
+	c.Response.CopyTo(&fctx.Response)
+
+	d := &Ctx{
+		RequestCtx: fctx,
+		Params:     make(map[string]string, len(c.Params)),
+		Locals:     make(map[string]interface{}, len(c.Locals)),
+		Logger:     c.Logger,
+		Auth:       c.Auth,
+		requestID:  c.requestID,
+		timedOut:   c.timedOut,
+		assets:     c.assets,
+		views:      c.views,
+		clock:      c.clock,
+		ctx:        c.ctx,
+		proxies:    c.proxies,
+		problems:   c.problems,
+		locale:     c.locale,
+		resolver:   c.resolver,
+		decode:     c.decode,
+		wrapper:    c.wrapper,
+		features:   c.features,
+	}
+	for k, v := range c.Params {
+		d.Params[k] = v
+	}
+	for k, v := range c.Locals {
+		d.Locals[k] = v
+	}
+	return d
+}
+
+func (c *Ctx) Adopt(d *Ctx) {
+	d.Response.CopyTo(&c.Response)
+	c.Auth = d.Auth
+	c.requestID = d.requestID
+	c.timedOut = c.timedOut || d.timedOut
+	for k, v := range d.Locals {
+		c.Locals[k] = v
+	}
+}
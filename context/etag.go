@@ -0,0 +1,27 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"fastrest/constant"
+)
+
+func (c *Ctx) JSONWithETag(status int, v interface{}) error {
+	if err := c.JSON(status, v); err != nil {
+		return err
+	}
+	if status < 200 || status >= 300 {
+		return nil
+	}
+
+	sum := sha256.Sum256(c.Response.Body())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Response.Header.Set("ETag", etag)
+
+	if (c.IsGet() || c.IsHead()) && etagMatches(c.Get("If-None-Match"), etag) {
+		c.Response.ResetBody()
+		c.Response.SetStatusCode(constant.StatusNotModified)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package context
+
+type FeatureChecker interface {
+	FeatureEnabled(c *Ctx, name string) bool
+}
+
+func (c *Ctx) SetFeatures(features FeatureChecker) {
+	c.features = features
+}
+
+func (c *Ctx) FeatureEnabled(name string) bool {
+	if c.features == nil {
+		return false
+	}
+	return c.features.FeatureEnabled(c, name)
+}
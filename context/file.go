@@ -0,0 +1,260 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"fastrest/constant"
+)
+
+func (c *Ctx) Download(path, name string) error {
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	c.Set("Content-Disposition", contentDisposition("attachment", name))
+	return c.SendFileRange(path)
+}
+
+func (c *Ctx) SendFileFrom(root, name string) error {
+	path, ok := safePath(root, name)
+	if !ok {
+		return c.BadRequest("invalid file path")
+	}
+	return c.sendFile(path)
+}
+
+func (c *Ctx) SendFileRange(path string) error {
+	if !cleanPath(path) {
+		return c.BadRequest("invalid file path")
+	}
+	return c.sendFile(path)
+}
+
+func (c *Ctx) sendFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			return c.NotFound("file not found")
+		}
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if info.IsDir() {
+		f.Close()
+		return c.NotFound("file not found")
+	}
+
+	return c.ServeContent(path, info.ModTime(), "", f)
+}
+
+func (c *Ctx) ServeContent(name string, modified time.Time, etag string, content io.ReadSeeker) error {
+	closer, _ := content.(io.Closer)
+	release := func() {
+		if closer != nil {
+			closer.Close()
+		}
+	}
+
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		release()
+		return err
+	}
+	modified = modified.UTC().Truncate(time.Second)
+	if etag == "" && !modified.IsZero() {
+		etag = fmt.Sprintf(`"%x-%x"`, modified.Unix(), size)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			release()
+			return err
+		}
+		var buf [512]byte
+		n, _ := io.ReadFull(content, buf[:])
+		contentType = http.DetectContentType(buf[:n])
+	}
+
+	c.Response.Header.SetContentType(contentType)
+	c.Set("Accept-Ranges", "bytes")
+	if etag != "" {
+		c.Set("ETag", etag)
+	}
+	if !modified.IsZero() {
+		c.Set("Last-Modified", modified.Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, modified) {
+		release()
+		c.Response.SetStatusCode(constant.StatusNotModified)
+		return nil
+	}
+
+	start, length := int64(0), size
+	if header := c.Get("Range"); header != "" && rangeApplies(c, etag, modified) {
+		s, l, ok := parseRange(header, size)
+		if !ok {
+			release()
+			c.Set("Content-Range", "bytes */"+strconv.FormatInt(size, 10))
+			return c.SendError(constant.StatusRequestedRangeNotSatisfiable, "range not satisfiable")
+		}
+		if l >= 0 {
+			start, length = s, l
+			c.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, size))
+			c.Response.SetStatusCode(constant.StatusPartialContent)
+		}
+	}
+	if c.Response.StatusCode() != constant.StatusPartialContent {
+		c.Response.SetStatusCode(constant.StatusOK)
+	}
+
+	if _, err := content.Seek(start, io.SeekStart); err != nil {
+		release()
+		return err
+	}
+	c.Response.SetBodyStream(&contentSection{Reader: io.LimitReader(content, length), closer: closer}, int(length))
+	return nil
+}
+
+type contentSection struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (s *contentSection) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+func safePath(root, name string) (string, bool) {
+	if root == "" || strings.IndexByte(name, 0) >= 0 {
+		return "", false
+	}
+	root = filepath.Clean(root)
+	path := filepath.Join(root, filepath.FromSlash(name))
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return path, true
+}
+
+func cleanPath(path string) bool {
+	if path == "" || strings.IndexByte(path, 0) >= 0 {
+		return false
+	}
+	for _, part := range strings.FieldsFunc(filepath.ToSlash(path), func(r rune) bool { return r == '/' }) {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func notModified(c *Ctx, etag string, modified time.Time) bool {
+	if match := c.Get("If-None-Match"); match != "" {
+		return etag != "" && etagMatches(match, etag)
+	}
+	if since := c.Get("If-Modified-Since"); since != "" && !modified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !modified.After(t)
+	}
+	return false
+}
+
+func rangeApplies(c *Ctx, etag string, modified time.Time) bool {
+	ifRange := c.Get("If-Range")
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		return ifRange == etag
+	}
+	t, err := http.ParseTime(ifRange)
+	return err == nil && !modified.IsZero() && modified.Equal(t)
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRange(header string, size int64) (start, length int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, -1, true
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, -1, true
+	}
+
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, -1, true
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, false
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, true
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, -1, true
+	}
+	if start >= size {
+		return 0, 0, false
+	}
+	end := size - 1
+	if last != "" {
+		e, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || e < start {
+			return 0, -1, true
+		}
+		if e < end {
+			end = e
+		}
+	}
+	return start, end - start + 1, true
+}
+
+func contentDisposition(kind, name string) string {
+	fallback := []rune(name)
+	for i, r := range fallback {
+		if r < 0x20 || r >= 0x7f || r == '"' || r == '\\' {
+			fallback[i] = '_'
+		}
+	}
+	value := kind + `; filename="` + string(fallback) + `"`
+	if string(fallback) != name {
+		value += "; filename*=UTF-8''" + strings.ReplaceAll(url.PathEscape(name), "+", "%2B")
+	}
+	return value
+}
@@ -0,0 +1,29 @@
+package context
+
+import "fmt"
+
+type Translator interface {
+	Lang() string
+	T(key string, args ...interface{}) string
+}
+
+func (c *Ctx) SetTranslator(t Translator) {
+	c.locale = t
+}
+
+func (c *Ctx) Locale() string {
+	if c.locale == nil {
+		return ""
+	}
+	return c.locale.Lang()
+}
+
+func (c *Ctx) T(key string, args ...interface{}) string {
+	if c.locale != nil {
+		return c.locale.T(key, args...)
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(key, args...)
+	}
+	return key
+}
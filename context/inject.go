@@ -0,0 +1,67 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrNoProvider = errors.New("context: no provider registered")
+
+type Resolver interface {
+	Resolve(c *Ctx, key string) (interface{}, error)
+}
+
+func (c *Ctx) SetResolver(r Resolver) {
+	c.resolver = r
+}
+
+func (c *Ctx) Resolve(key string) (interface{}, error) {
+	if c.resolver == nil {
+		return nil, fmt.Errorf("%w for %q", ErrNoProvider, key)
+	}
+	return c.resolver.Resolve(c, key)
+}
+
+func Inject[T any](c *Ctx) (T, error) {
+	return ResolveAs[T](c, TypeKey[T]())
+}
+
+func ResolveAs[T any](c *Ctx, key string) (T, error) {
+	var zero T
+	v, err := c.Resolve(key)
+	if err != nil {
+		return zero, err
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("context: provider %q returned %T, not %s", key, v, reflect.TypeFor[T]())
+	}
+	return t, nil
+}
+
+func TypeKey[T any]() string {
+	return typeKey(reflect.TypeFor[T]())
+}
+
+func typeKey(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		return t.PkgPath() + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + typeKey(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeKey(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), typeKey(t.Elem()))
+	case reflect.Map:
+		return "map[" + typeKey(t.Key()) + "]" + typeKey(t.Elem())
+	case reflect.Chan:
+		return t.ChanDir().String() + " " + typeKey(t.Elem())
+	}
+	return t.String()
+}
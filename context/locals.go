@@ -0,0 +1,47 @@
+package context
+
+type Key[T any] string
+
+const (
+	RequestIDKey Key[string]    = "request_id"
+	AuthKey      Key[*AuthInfo] = "auth"
+	TraceIDKey   Key[string]    = "trace_id"
+	SpanIDKey    Key[string]    = "span_id"
+	TenantKey    Key[string]    = "tenant"
+
+	AllowedMethodsKey Key[[]string] = "allowed_methods"
+	RoutePatternKey   Key[string]   = "route_pattern"
+	CacheTagsKey      Key[[]string] = "cache_tags"
+)
+
+func (k Key[T]) Get(c *Ctx) (T, bool) {
+	return Local[T](c, string(k))
+}
+
+func (k Key[T]) Must(c *Ctx) T {
+	v, ok := k.Get(c)
+	if !ok {
+		panic("context: local " + string(k) + " is not set")
+	}
+	return v
+}
+
+func (k Key[T]) Set(c *Ctx, value T) {
+	c.Locals[string(k)] = value
+}
+
+func (k Key[T]) Delete(c *Ctx) {
+	delete(c.Locals, string(k))
+}
+
+func Local[T any](c *Ctx, key string) (T, bool) {
+	v, ok := c.Locals[key].(T)
+	return v, ok
+}
+
+func LocalOr[T any](c *Ctx, key string, fallback T) T {
+	if v, ok := Local[T](c, key); ok {
+		return v
+	}
+	return fallback
+}
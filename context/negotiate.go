@@ -0,0 +1,120 @@
+package context
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func (c *Ctx) Is(types ...string) bool {
+	ct := mediaType(c.Get("Content-Type"))
+	if ct == "" {
+		return false
+	}
+	for _, t := range types {
+		if matchMedia(expandMediaType(t), ct) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Ctx) Accepts(offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	header := c.Get("Accept")
+	if header == "" {
+		return offers[0]
+	}
+
+	ranges := parseAccept(header)
+	for _, r := range ranges {
+		for _, offer := range offers {
+			if matchMedia(r.media, expandMediaType(offer)) {
+				return offer
+			}
+		}
+	}
+	return ""
+}
+
+type acceptRange struct {
+	media string
+	q     float64
+}
+
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		media, params, _ := strings.Cut(part, ";")
+		media = strings.ToLower(strings.TrimSpace(media))
+		if media == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, acceptRange{media: media, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+		return specificity(ranges[i].media) > specificity(ranges[j].media)
+	})
+	return ranges
+}
+
+func specificity(media string) int {
+	switch {
+	case media == "*/*":
+		return 0
+	case strings.HasSuffix(media, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func matchMedia(pattern, media string) bool {
+	if pattern == "*/*" || pattern == media {
+		return true
+	}
+	pType, pSub, _ := strings.Cut(pattern, "/")
+	mType, mSub, _ := strings.Cut(media, "/")
+	if pType != mType {
+		return false
+	}
+	if pSub == "*" || mSub == "*" {
+		return true
+	}
+	if suffix, ok := strings.CutPrefix(pSub, "*+"); ok {
+		return strings.HasSuffix(mSub, "+"+suffix)
+	}
+	return false
+}
+
+func expandMediaType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(t))
+	if strings.Contains(t, "/") {
+		return mediaType(t)
+	}
+	if byExt := mime.TypeByExtension("." + t); byExt != "" {
+		return mediaType(byExt)
+	}
+	return t
+}
+
+func mediaType(header string) string {
+	media, _, _ := strings.Cut(header, ";")
+	return strings.ToLower(strings.TrimSpace(media))
+}
@@ -0,0 +1,162 @@
+package context
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"fastrest/constant"
+)
+
+type PageDefaults struct {
+	Limit       int
+	MaxLimit    int
+	PageParam   string
+	LimitParam  string
+	CursorParam string
+}
+
+type Page struct {
+	Number     int
+	Limit      int
+	Offset     int
+	Cursor     string
+	NextCursor string
+	PrevCursor string
+
+	defaults PageDefaults
+}
+
+type Pagination struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      *int   `json:"total,omitempty"`
+	TotalPages *int   `json:"total_pages,omitempty"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type PagedResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination *Pagination `json:"pagination"`
+}
+
+func (c *Ctx) Paginate(defaults *PageDefaults) *Page {
+	d := PageDefaults{}
+	if defaults != nil {
+		d = *defaults
+	}
+	if d.Limit <= 0 {
+		d.Limit = 20
+	}
+	if d.MaxLimit <= 0 {
+		d.MaxLimit = 100
+	}
+	if d.Limit > d.MaxLimit {
+		d.Limit = d.MaxLimit
+	}
+	if d.PageParam == "" {
+		d.PageParam = "page"
+	}
+	if d.LimitParam == "" {
+		d.LimitParam = "limit"
+	}
+	if d.CursorParam == "" {
+		d.CursorParam = "cursor"
+	}
+
+	limit := c.QueryIntDefault(d.LimitParam, d.Limit)
+	if limit < 1 {
+		limit = d.Limit
+	}
+	if limit > d.MaxLimit {
+		limit = d.MaxLimit
+	}
+
+	number := c.QueryIntDefault(d.PageParam, 1)
+	if number < 1 {
+		number = 1
+	}
+	if maxPage := math.MaxInt32 / limit; number > maxPage {
+		number = maxPage
+	}
+
+	p := &Page{
+		Number:   number,
+		Limit:    limit,
+		Cursor:   c.Query(d.CursorParam),
+		defaults: d,
+	}
+	if p.Cursor == "" {
+		p.Offset = (number - 1) * limit
+	}
+	return p
+}
+
+func (c *Ctx) Paginated(p *Page, items interface{}, total int) error {
+	meta := &Pagination{Limit: p.Limit}
+	var links []string
+
+	if p.Cursor != "" || p.NextCursor != "" || p.PrevCursor != "" {
+		meta.NextCursor = p.NextCursor
+		meta.PrevCursor = p.PrevCursor
+		if p.NextCursor != "" {
+			meta.Next = c.pageURL(p, 0, p.NextCursor)
+			links = append(links, link(meta.Next, "next"))
+		}
+		if p.PrevCursor != "" {
+			meta.Prev = c.pageURL(p, 0, p.PrevCursor)
+			links = append(links, link(meta.Prev, "prev"))
+		}
+	} else {
+		meta.Page = p.Number
+		hasNext := total < 0 || p.Offset+p.Limit < total
+		lastPage := 0
+		if total >= 0 {
+			totalPages := (total + p.Limit - 1) / p.Limit
+			meta.Total, meta.TotalPages = &total, &totalPages
+			lastPage = max(totalPages, 1)
+			c.Set("X-Total-Count", strconv.Itoa(total))
+		}
+		if hasNext {
+			meta.Next = c.pageURL(p, p.Number+1, "")
+			links = append(links, link(meta.Next, "next"))
+		}
+		if p.Number > 1 {
+			meta.Prev = c.pageURL(p, p.Number-1, "")
+			links = append(links, link(meta.Prev, "prev"))
+		}
+		links = append(links, link(c.pageURL(p, 1, ""), "first"))
+		if lastPage > 0 {
+			links = append(links, link(c.pageURL(p, lastPage, ""), "last"))
+		}
+	}
+
+	if len(links) > 0 {
+		c.Set("Link", strings.Join(links, ", "))
+	}
+	return c.JSON(constant.StatusOK, &PagedResponse{Data: items, Pagination: meta})
+}
+
+func (c *Ctx) pageURL(p *Page, number int, cursor string) string {
+	var args fasthttp.Args
+	c.QueryArgs().CopyTo(&args)
+	args.Set(p.defaults.LimitParam, strconv.Itoa(p.Limit))
+	if cursor != "" {
+		args.Del(p.defaults.PageParam)
+		args.Set(p.defaults.CursorParam, cursor)
+	} else {
+		args.Del(p.defaults.CursorParam)
+		args.Set(p.defaults.PageParam, strconv.Itoa(number))
+	}
+
+	return c.BaseURL() + c.Path() + "?" + args.String()
+}
+
+func link(url, rel string) string {
+	return "<" + url + `>; rel="` + rel + `"`
+}
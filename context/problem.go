@@ -0,0 +1,103 @@
+package context
+
+import (
+	"encoding/json"
+
+	"fastrest/constant"
+)
+
+const ProblemContentType = "application/problem+json"
+
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		out[k] = v
+	}
+	out["type"] = p.Type
+	out["title"] = p.Title
+	out["status"] = p.Status
+	if p.Detail != "" {
+		out["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		out["instance"] = p.Instance
+	}
+	return json.Marshal(out)
+}
+
+func NewProblem(status int, typ, title, detail string) *Problem {
+	if typ == "" {
+		typ = "about:blank"
+	}
+	if title == "" {
+		title = constant.StatusText(status)
+	}
+	return &Problem{Type: typ, Title: title, Status: status, Detail: detail}
+}
+
+func (c *Ctx) Problem(status int, typ, title, detail string, extensions ...map[string]interface{}) error {
+	p := NewProblem(status, typ, title, detail)
+	p.Instance = c.Path()
+	for _, ext := range extensions {
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{}, len(ext))
+		}
+		for k, v := range ext {
+			p.Extensions[k] = v
+		}
+	}
+	return c.SendProblem(p)
+}
+
+func (c *Ctx) SendProblem(p *Problem) error {
+	if err := c.RawJSON(p.Status, p); err != nil {
+		return err
+	}
+	c.Response.Header.SetContentType(ProblemContentType)
+	return nil
+}
+
+func (c *Ctx) SetResponseWrapper(fn ResponseWrapper) {
+	c.wrapper = fn
+}
+
+func (c *Ctx) SetProblemDetails(enabled bool) {
+	c.problems = enabled
+}
+
+func (c *Ctx) ProblemDetails() bool {
+	return c.problems
+}
+
+func (c *Ctx) SendError(status int, msg string) error {
+	return c.SendErrorDetails(status, msg, nil)
+}
+
+func (c *Ctx) SendErrorDetails(status int, msg string, details interface{}) error {
+	if c.locale != nil {
+		msg = c.locale.T(msg)
+	}
+	if c.problems {
+		p := NewProblem(status, "", "", msg)
+		p.Instance = c.Path()
+		if details != nil {
+			p.Extensions = map[string]interface{}{"details": details}
+		}
+		return c.SendProblem(p)
+	}
+	return c.JSON(status, &ErrorBody{Error: msg, Details: details})
+}
+
+type ErrorBody struct {
+	Error   string      `json:"error"`
+	Details interface{} `json:"details,omitempty"`
+}
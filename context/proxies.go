@@ -0,0 +1,201 @@
+package context
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+var proxyAliases = map[string][]string{
+	"loopback": {"127.0.0.0/8", "::1/128"},
+	"private":  {"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"},
+}
+
+type TrustedProxies struct {
+	nets []*net.IPNet
+}
+
+func ParseTrustedProxies(entries []string) (*TrustedProxies, error) {
+	t := &TrustedProxies{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		cidrs, ok := proxyAliases[strings.ToLower(entry)]
+		if !ok {
+			cidrs = []string{entry}
+		}
+		for _, cidr := range cidrs {
+			if !strings.Contains(cidr, "/") {
+				ip := net.ParseIP(cidr)
+				if ip == nil {
+					return nil, fmt.Errorf("trusted proxies: invalid IP %q", cidr)
+				}
+				bits := 128
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				t.nets = append(t.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+			_, n, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxies: invalid CIDR %q", cidr)
+			}
+			t.nets = append(t.nets, n)
+		}
+	}
+	return t, nil
+}
+
+func (t *TrustedProxies) Contains(ip net.IP) bool {
+	if t == nil || ip == nil {
+		return false
+	}
+	for _, n := range t.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Ctx) SetTrustedProxies(t *TrustedProxies) {
+	c.proxies = t
+}
+
+func (c *Ctx) PeerIP() string {
+	return c.RemoteIP().String()
+}
+
+func (c *Ctx) IP() string {
+	peer := c.RemoteIP()
+	if !c.proxies.Contains(peer) {
+		return peer.String()
+	}
+
+	if xff := c.Get("X-Forwarded-For"); xff != "" {
+		if ip := c.resolveChain(strings.Split(xff, ",")); ip != "" {
+			return ip
+		}
+	}
+	if fwd := c.Get("Forwarded"); fwd != "" {
+		if ip := c.resolveChain(forwardedFor(fwd)); ip != "" {
+			return ip
+		}
+	}
+	if realIP := net.ParseIP(strings.TrimSpace(c.Get("X-Real-IP"))); realIP != nil {
+		return realIP.String()
+	}
+	return peer.String()
+}
+
+func (c *Ctx) Scheme() string {
+	if c.proxies.Contains(c.RemoteIP()) {
+		proto := firstValue(c.Get("X-Forwarded-Proto"))
+		if proto == "" {
+			proto = forwardedParam(c.Get("Forwarded"), "proto")
+		}
+		if proto = strings.ToLower(proto); proto == "http" || proto == "https" {
+			return proto
+		}
+	}
+	if c.IsTLS() {
+		return "https"
+	}
+	return "http"
+}
+
+func (c *Ctx) Hostname() string {
+	host := ""
+	if c.proxies.Contains(c.RemoteIP()) {
+		host = firstValue(c.Get("X-Forwarded-Host"))
+		if host == "" {
+			host = forwardedParam(c.Get("Forwarded"), "host")
+		}
+	}
+	if host == "" {
+		host = string(c.Host())
+	}
+	if !validHost(host) {
+		return ""
+	}
+	return host
+}
+
+func (c *Ctx) BaseURL() string {
+	host := c.Hostname()
+	if host == "" {
+		return ""
+	}
+	return c.Scheme() + "://" + host
+}
+
+func firstValue(header string) string {
+	first, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(first)
+}
+
+func forwardedParam(header, name string) string {
+	element, _, _ := strings.Cut(header, ",")
+	for _, pair := range strings.Split(element, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && strings.EqualFold(key, name) {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+func validHost(host string) bool {
+	if host == "" || len(host) > 255 {
+		return false
+	}
+	for i := 0; i < len(host); i++ {
+		ch := host[i]
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '.' || ch == '-' || ch == ':' || ch == '[' || ch == ']' || ch == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Ctx) resolveChain(hops []string) string {
+	var leftmost string
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := parseHop(hops[i])
+		if ip == nil {
+			return leftmost
+		}
+		leftmost = ip.String()
+		if !c.proxies.Contains(ip) {
+			return leftmost
+		}
+	}
+	return leftmost
+}
+
+func forwardedFor(header string) []string {
+	var hops []string
+	for _, element := range strings.Split(header, ",") {
+		for _, pair := range strings.Split(element, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(key, "for") {
+				hops = append(hops, strings.Trim(value, `"`))
+			}
+		}
+	}
+	return hops
+}
+
+func parseHop(hop string) net.IP {
+	hop = strings.TrimSpace(hop)
+	if host, _, err := net.SplitHostPort(hop); err == nil {
+		hop = host
+	}
+	return net.ParseIP(strings.Trim(hop, "[]"))
+}
@@ -0,0 +1,109 @@
+package context
+
+import (
+	"errors"
+	"mime/multipart"
+	"reflect"
+	"strings"
+)
+
+func (c *Ctx) QueryParser(v interface{}) error {
+	err := c.parseQuery(v)
+	if err != nil {
+		c.sendBindError(err)
+	}
+	return err
+}
+
+func (c *Ctx) parseQuery(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return &BindError{Source: "target", Err: errors.New("QueryParser requires a non-nil pointer to a struct")}
+	}
+
+	values := make(map[string][]string)
+	for k, val := range c.QueryArgs().All() {
+		key := strings.TrimSuffix(string(k), "[]")
+		values[key] = append(values[key], string(val))
+	}
+	return parseValues(rv.Elem(), valueSource{tag: "query", values: values}, "")
+}
+
+type valueSource struct {
+	tag    string
+	values map[string][]string
+	files  map[string][]*multipart.FileHeader
+}
+
+func parseValues(v reflect.Value, src valueSource, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		field := v.Field(i)
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if err := parseValues(field, src, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+		name := sf.Tag.Get(src.tag)
+		if !sf.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "[" + name + "]"
+		}
+
+		switch {
+		case sf.Type == fileHeaderType:
+			if files := src.files[key]; len(files) > 0 {
+				field.Set(reflect.ValueOf(files[0]))
+			}
+		case sf.Type == fileHeadersType:
+			if files := src.files[key]; len(files) > 0 {
+				field.Set(reflect.ValueOf(files))
+			}
+		case sf.Type.Kind() == reflect.Struct && sf.Type != timeType:
+			if err := parseValues(field, src, key); err != nil {
+				return err
+			}
+		case sf.Type.Kind() == reflect.Map && sf.Type.Key().Kind() == reflect.String:
+			if err := parseValuesMap(field, src, key, sf.Tag.Get("layout")); err != nil {
+				return err
+			}
+		default:
+			raw, ok := src.values[key]
+			if !ok {
+				continue
+			}
+			if err := setBindValue(field, raw, sf.Tag.Get("layout"), false); err != nil {
+				return &BindError{Source: src.tag, Field: key, Value: strings.Join(raw, ","), Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+func parseValuesMap(field reflect.Value, src valueSource, key, layout string) error {
+	open := key + "["
+	for k, raw := range src.values {
+		if !strings.HasPrefix(k, open) || !strings.HasSuffix(k, "]") {
+			continue
+		}
+		name := k[len(open) : len(k)-1]
+		if name == "" || strings.ContainsAny(name, "[]") {
+			continue
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		elem := reflect.New(field.Type().Elem()).Elem()
+		if err := setBindValue(elem, raw, layout, false); err != nil {
+			return &BindError{Source: src.tag, Field: k, Value: strings.Join(raw, ","), Err: err}
+		}
+		field.SetMapIndex(reflect.ValueOf(name).Convert(field.Type().Key()), elem)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package context
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+var ErrNoRenderer = errors.New("render: no view renderer configured")
+
+type Renderer interface {
+	Render(w io.Writer, name string, data interface{}, layouts ...string) error
+}
+
+func (c *Ctx) SetViews(views Renderer) {
+	c.views = views
+}
+
+func (c *Ctx) Render(status int, name string, data interface{}, layouts ...string) error {
+	if c.views == nil {
+		return ErrNoRenderer
+	}
+	var buf bytes.Buffer
+	if err := c.views.Render(&buf, name, data, layouts...); err != nil {
+		return err
+	}
+	c.Response.Header.SetContentType("text/html; charset=utf-8")
+	c.Response.SetStatusCode(status)
+	c.Response.SetBody(buf.Bytes())
+	return nil
+}
@@ -0,0 +1,214 @@
+package context
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const ScopeTag = "scope"
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (a *AuthInfo) HasScope(scope string) bool {
+	if a == nil {
+		return false
+	}
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Ctx) ScopedJSON(status int, v interface{}) error {
+	var scopes []string
+	if c.Auth != nil && c.Auth.Valid {
+		scopes = c.Auth.Scopes
+	}
+
+	data, err := MarshalScoped(v, scopes)
+	if err != nil {
+		return err
+	}
+	c.Response.Header.SetContentType("application/json")
+	c.Response.SetStatusCode(status)
+	c.Response.SetBody(data)
+	return nil
+}
+
+func MarshalScoped(v interface{}, scopes []string) ([]byte, error) {
+	granted := make(map[string]bool, len(scopes))
+	for _, s := range scopes {
+		granted[s] = true
+	}
+
+	var buf bytes.Buffer
+	if err := encodeScoped(&buf, reflect.ValueOf(v), granted); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeScoped(buf *bytes.Buffer, rv reflect.Value, granted map[string]bool) error {
+	if !rv.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+
+	t := rv.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		(rv.CanAddr() && (reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType))) {
+		return writeJSON(buf, rv.Interface())
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return encodeScoped(buf, rv.Elem(), granted)
+	case reflect.Struct:
+		buf.WriteByte('{')
+		if _, err := encodeFields(buf, rv, granted, true); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+		return nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return writeJSON(buf, rv.Interface())
+		}
+		fallthrough
+	case reflect.Array:
+		buf.WriteByte('[')
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeScoped(buf, rv.Index(i), granted); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case reflect.Map:
+		if rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if t.Key().Kind() != reflect.String {
+			return writeJSON(buf, rv.Interface())
+		}
+		entries := make(map[string]json.RawMessage, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			var elem bytes.Buffer
+			if err := encodeScoped(&elem, iter.Value(), granted); err != nil {
+				return err
+			}
+			entries[iter.Key().String()] = elem.Bytes()
+		}
+		return writeJSON(buf, entries)
+	default:
+		return writeJSON(buf, rv.Interface())
+	}
+}
+
+func encodeFields(buf *bytes.Buffer, rv reflect.Value, granted map[string]bool, first bool) (bool, error) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if scope := field.Tag.Get(ScopeTag); scope != "" && !scopeGranted(scope, granted) {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv, ft = fv.Elem(), ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				var err error
+				if first, err = encodeFields(buf, fv, granted, first); err != nil {
+					return first, err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if strings.Contains(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		if err := writeJSON(buf, name); err != nil {
+			return first, err
+		}
+		buf.WriteByte(':')
+		if err := encodeScoped(buf, fv, granted); err != nil {
+			return first, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+	return first, nil
+}
+
+func scopeGranted(scope string, granted map[string]bool) bool {
+	for _, s := range strings.Split(scope, ",") {
+		if granted[strings.TrimSpace(s)] {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Ptr, reflect.Interface:
+		return v.IsZero()
+	}
+	return false
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
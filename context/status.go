@@ -0,0 +1,34 @@
+package context
+
+import (
+	stdctx "context"
+	"errors"
+
+	"fastrest/constant"
+)
+
+var ErrRecordNotFound = errors.New("record not found")
+
+func ErrorStatus(c *Ctx, err error) int {
+	status := c.Response.StatusCode()
+	if status == 0 {
+		status = constant.StatusOK
+	}
+	if err == nil {
+		return status
+	}
+	var sc interface{ StatusCode() int }
+	if errors.As(err, &sc) {
+		return sc.StatusCode()
+	}
+	if status != constant.StatusOK || len(c.Response.Body()) > 0 {
+		return status
+	}
+	switch {
+	case errors.Is(err, ErrRecordNotFound):
+		return constant.StatusNotFound
+	case errors.Is(err, stdctx.DeadlineExceeded):
+		return constant.StatusGatewayTimeout
+	}
+	return constant.StatusInternalServerError
+}
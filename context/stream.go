@@ -0,0 +1,44 @@
+package context
+
+import (
+	"bufio"
+
+	"github.com/valyala/fasthttp"
+)
+
+const earlyHintsKey = "fastrest.early_hints"
+
+type EarlyHintsFunc func(links []string) error
+
+func SetEarlyHints(fctx *fasthttp.RequestCtx, fn EarlyHintsFunc) {
+	fctx.SetUserValue(earlyHintsKey, fn)
+}
+
+func (c *Ctx) EarlyHints(links ...string) error {
+	for _, link := range links {
+		c.Response.Header.Add("Link", link)
+	}
+	if fn, ok := c.UserValue(earlyHintsKey).(EarlyHintsFunc); ok {
+		all := make([]string, 0, len(links))
+		for _, v := range c.Response.Header.PeekAll("Link") {
+			all = append(all, string(v))
+		}
+		return fn(all)
+	}
+	return c.RequestCtx.EarlyHints()
+}
+
+func (c *Ctx) Stream(status int, fn func(w *bufio.Writer) error) error {
+	logger := c.Logger
+	path := c.Path()
+	c.Response.SetStatusCode(status)
+	c.Response.ImmediateHeaderFlush = true
+	c.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := fn(w); err != nil {
+			logger.Warn("stream failed", "error", err.Error(), "path", path)
+			return
+		}
+		w.Flush()
+	})
+	return nil
+}
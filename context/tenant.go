@@ -0,0 +1,10 @@
+package context
+
+func (c *Ctx) Tenant() string {
+	tenant, _ := TenantKey.Get(c)
+	return tenant
+}
+
+func (c *Ctx) SetTenant(tenant string) {
+	TenantKey.Set(c, tenant)
+}
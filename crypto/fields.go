@@ -0,0 +1,137 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"fastrest/context"
+)
+
+const TagName = "encrypt"
+
+func (r *KeyRing) EncryptFields(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("crypto: EncryptFields requires a non-nil pointer")
+	}
+	return walkFields(rv.Elem(), r.encryptString, false)
+}
+
+func (r *KeyRing) DecryptFields(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("crypto: DecryptFields requires a non-nil pointer")
+	}
+	return walkFields(rv.Elem(), r.decryptString, false)
+}
+
+func (r *KeyRing) Bind(c *context.Ctx, v interface{}) error {
+	if err := c.Bind(v); err != nil {
+		return err
+	}
+	return r.DecryptFields(v)
+}
+
+func (r *KeyRing) JSON(c *context.Ctx, status int, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return c.JSON(status, v)
+	}
+	cp := reflect.New(rv.Type()).Elem()
+	cp.Set(rv)
+	if err := walkFields(cp, r.encryptString, true); err != nil {
+		return err
+	}
+	return c.JSON(status, cp.Interface())
+}
+
+func (r *KeyRing) encryptString(s string) (string, error) {
+	return r.Encrypt([]byte(s))
+}
+
+func (r *KeyRing) decryptString(s string) (string, error) {
+	plaintext, err := r.Decrypt(s)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func walkFields(v reflect.Value, fn func(string) (string, error), clone bool) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		if clone {
+			cp := reflect.New(v.Type().Elem())
+			cp.Elem().Set(v.Elem())
+			v.Set(cp)
+		}
+		return walkFields(v.Elem(), fn, clone)
+
+	case reflect.Slice, reflect.Array:
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Slice, reflect.Array:
+		default:
+			return nil
+		}
+		if clone && v.Kind() == reflect.Slice && !v.IsNil() {
+			cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			reflect.Copy(cp, v)
+			v.Set(cp)
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := walkFields(v.Index(i), fn, clone); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			field := v.Field(i)
+			if sf.Tag.Get(TagName) != "true" {
+				if err := walkFields(field, fn, clone); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := transformField(field, fn, clone); err != nil {
+				return fmt.Errorf("crypto: field %s: %w", sf.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func transformField(field reflect.Value, fn func(string) (string, error), clone bool) error {
+	if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.String {
+		if field.IsNil() {
+			return nil
+		}
+		if clone {
+			cp := reflect.New(field.Type().Elem())
+			cp.Elem().Set(field.Elem())
+			field.Set(cp)
+		}
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.String {
+		return errors.New("only string fields can be encrypted")
+	}
+	if field.String() == "" {
+		return nil
+	}
+	out, err := fn(field.String())
+	if err != nil {
+		return err
+	}
+	field.SetString(out)
+	return nil
+}
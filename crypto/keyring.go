@@ -0,0 +1,195 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const envelopeVersion = "v1"
+
+var (
+	ErrInvalidKey = errors.New("crypto: key must be 16, 24, or 32 bytes")
+	ErrUnknownKey = errors.New("crypto: unknown key id")
+	ErrMalformed  = errors.New("crypto: malformed envelope")
+)
+
+type KeyRing struct {
+	mu      sync.RWMutex
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+func NewKeyRing(primaryID string, primaryKey []byte) (*KeyRing, error) {
+	r := &KeyRing{keys: make(map[string]cipher.AEAD)}
+	if err := r.Rotate(primaryID, primaryKey); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *KeyRing) Add(id string, key []byte) error {
+	if id == "" || strings.Contains(id, ".") {
+		return fmt.Errorf("crypto: invalid key id %q", id)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[id] = aead
+	return nil
+}
+
+func (r *KeyRing) Rotate(id string, key []byte) error {
+	if err := r.Add(id, key); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.primary = id
+	return nil
+}
+
+func (r *KeyRing) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id == r.primary {
+		return fmt.Errorf("crypto: cannot remove primary key %q", id)
+	}
+	delete(r.keys, id)
+	return nil
+}
+
+func (r *KeyRing) Primary() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.primary
+}
+
+func (r *KeyRing) Encrypt(plaintext []byte) (string, error) {
+	r.mu.RLock()
+	id, kek := r.primary, r.keys[r.primary]
+	r.mu.RUnlock()
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	dek, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(kek, dataKey, []byte(id))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dek, plaintext, []byte(id))
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	return envelopeVersion + "." + id + "." + enc.EncodeToString(wrapped) + "." + enc.EncodeToString(sealed), nil
+}
+
+func (r *KeyRing) Decrypt(envelope string) ([]byte, error) {
+	id, wrapped, sealed, err := parseEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	kek, ok := r.keys[id]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+
+	dataKey, err := open(kek, wrapped, []byte(id))
+	if err != nil {
+		return nil, err
+	}
+	dek, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return open(dek, sealed, []byte(id))
+}
+
+func (r *KeyRing) NeedsRotation(envelope string) bool {
+	id, _, _, err := parseEnvelope(envelope)
+	return err == nil && id != r.Primary()
+}
+
+func (r *KeyRing) Reencrypt(envelope string) (string, error) {
+	if !r.NeedsRotation(envelope) {
+		return envelope, nil
+	}
+	plaintext, err := r.Decrypt(envelope)
+	if err != nil {
+		return "", err
+	}
+	return r.Encrypt(plaintext)
+}
+
+func IsEnvelope(s string) bool {
+	_, _, _, err := parseEnvelope(s)
+	return err == nil
+}
+
+func parseEnvelope(envelope string) (string, []byte, []byte, error) {
+	parts := strings.Split(envelope, ".")
+	if len(parts) != 4 || parts[0] != envelopeVersion || parts[1] == "" {
+		return "", nil, nil, ErrMalformed
+	}
+	wrapped, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	return parts[1], wrapped, sealed, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, data), nil
+}
+
+func open(aead cipher.AEAD, sealed, data []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], data)
+	if err != nil {
+		return nil, errors.New("crypto: decryption failed")
+	}
+	return plaintext, nil
+}
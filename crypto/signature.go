@@ -0,0 +1,78 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+type SignatureHeaders struct {
+	Signature string
+	KeyID     string
+	Timestamp string
+	Nonce     string
+}
+
+func DefaultSignatureHeaders() SignatureHeaders {
+	return SignatureHeaders{
+		Signature: "X-Signature",
+		KeyID:     "X-Signature-Key-Id",
+		Timestamp: "X-Signature-Timestamp",
+		Nonce:     "X-Signature-Nonce",
+	}
+}
+
+func (h SignatureHeaders) WithDefaults() SignatureHeaders {
+	d := DefaultSignatureHeaders()
+	if h.Signature == "" {
+		h.Signature = d.Signature
+	}
+	if h.KeyID == "" {
+		h.KeyID = d.KeyID
+	}
+	if h.Timestamp == "" {
+		h.Timestamp = d.Timestamp
+	}
+	if h.Nonce == "" {
+		h.Nonce = d.Nonce
+	}
+	return h
+}
+
+func CanonicalRequest(method, requestURI string, timestamp int64, nonce string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		requestURI,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+}
+
+func Sign(secret []byte, canonical string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifySignature(secret []byte, canonical, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonical))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func NewNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,80 @@
+package fastrest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+type DeprecationOption func(*deprecation)
+
+type deprecation struct {
+	forward bool
+	sunset  time.Time
+}
+
+func ForwardDeprecated() DeprecationOption {
+	return func(d *deprecation) {
+		d.forward = true
+	}
+}
+
+func SunsetAt(t time.Time) DeprecationOption {
+	return func(d *deprecation) {
+		d.sunset = t
+	}
+}
+
+func (a *App) DeprecateRoute(oldPath, newPath string, opts ...DeprecationOption) {
+	d := &deprecation{}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	a.Any(oldPath, func(c *context.Ctx) error {
+		target := expandPath(newPath, c.Params)
+		method := c.Method()
+
+		a.metrics.Counter("deprecated_route_requests_total",
+			"method", method, "path", oldPath, "successor", newPath).Inc()
+
+		c.Set("Deprecation", "true")
+		c.Set("Link", "<"+target+`>; rel="successor-version"`)
+		if !d.sunset.IsZero() {
+			c.Set("Sunset", d.sunset.UTC().Format(http.TimeFormat))
+		}
+
+		if query := c.QueryArgs().QueryString(); len(query) > 0 {
+			target += "?" + string(query)
+		}
+		if !d.forward {
+			return c.Redirect(target, constant.StatusPermanentRedirect)
+		}
+
+		route, params := a.router.find(method, string(c.Host()), expandPath(newPath, c.Params), nil)
+		if route == nil || len(route.Handlers) == 0 {
+			return c.NotFound("not found")
+		}
+		clear(c.Params)
+		for _, p := range params {
+			c.Params[p.key] = p.value
+		}
+		c.Request.SetRequestURI(target)
+		return wrapMiddleware(route.middleware, composeHandlers(route.Handlers))(c)
+	})
+}
+
+func expandPath(pattern string, params map[string]string) string {
+	parts := strings.Split(pattern, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = params[part[1:]]
+		} else if part == "*" && i == len(parts)-1 {
+			parts[i] = params["*"]
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,200 @@
+package fastrest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"fastrest/context"
+	"fastrest/pkg/logging"
+)
+
+type ReloadHook func(changed []string) error
+
+type devMode struct {
+	paths    []string
+	interval time.Duration
+	logger   logging.Logger
+	mu       sync.Mutex
+	hooks    []ReloadHook
+	mtimes   map[string]time.Time
+	stop     chan struct{}
+	stopOnce sync.Once
+	reloaded func()
+}
+
+type panicError struct {
+	value interface{}
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+func newDevMode(paths []string, interval time.Duration, logger logging.Logger) *devMode {
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	return &devMode{
+		paths:    paths,
+		interval: interval,
+		logger:   logger,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (a *App) OnReload(hook ReloadHook) {
+	if a.dev == nil {
+		return
+	}
+	a.dev.mu.Lock()
+	a.dev.hooks = append(a.dev.hooks, hook)
+	a.dev.mu.Unlock()
+}
+
+func (d *devMode) start() {
+	if len(d.paths) == 0 {
+		return
+	}
+	d.mtimes = d.scan()
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				d.poll()
+			case <-d.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (d *devMode) close() {
+	d.stopOnce.Do(func() { close(d.stop) })
+}
+
+func (d *devMode) scan() map[string]time.Time {
+	mtimes := make(map[string]time.Time)
+	for _, root := range d.paths {
+		filepath.WalkDir(root, func(p string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if entry.IsDir() {
+				if p != root && strings.HasPrefix(entry.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info, err := entry.Info(); err == nil {
+				mtimes[p] = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return mtimes
+}
+
+func (d *devMode) poll() {
+	current := d.scan()
+	var changed []string
+	for p, mtime := range current {
+		if prev, ok := d.mtimes[p]; !ok || !prev.Equal(mtime) {
+			changed = append(changed, p)
+		}
+	}
+	for p := range d.mtimes {
+		if _, ok := current[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+	d.mtimes = current
+	if len(changed) == 0 {
+		return
+	}
+	sort.Strings(changed)
+	d.reload(changed)
+}
+
+func (d *devMode) reload(changed []string) {
+	d.logger.Info("files changed, reloading", "files", strings.Join(changed, ","))
+
+	d.mu.Lock()
+	hooks := append([]ReloadHook{}, d.hooks...)
+	d.mu.Unlock()
+	for _, hook := range hooks {
+		if err := hook(changed); err != nil {
+			d.logger.Error("reload hook failed", "error", err.Error())
+		}
+	}
+	if d.reloaded != nil {
+		d.reloaded()
+	}
+}
+
+func (a *App) callHandler(handler context.Handler, c *context.Ctx) (err error) {
+	if a.dev == nil {
+		return handler(c)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{value: r, stack: debug.Stack()}
+		}
+	}()
+	return handler(c)
+}
+
+func (a *App) describeError(c *context.Ctx, status int, err error) {
+	details := map[string]interface{}{
+		"error": err.Error(),
+		"type":  fmt.Sprintf("%T", err),
+		"route": c.Path(),
+	}
+	var chain []string
+	for e := errors.Unwrap(err); e != nil; e = errors.Unwrap(e) {
+		chain = append(chain, e.Error())
+	}
+	if len(chain) > 0 {
+		details["chain"] = chain
+	}
+	var pe *panicError
+	if errors.As(err, &pe) {
+		details["stack"] = strings.Split(strings.TrimSpace(string(pe.stack)), "\n")
+		c.Logger.Error("handler panic", "panic", fmt.Sprint(pe.value), "stack", string(pe.stack))
+	}
+	c.Response.ResetBody()
+	c.SendErrorDetails(status, err.Error(), details)
+}
+
+func (a *App) printRoutes(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tNAME\tHANDLER")
+	for _, r := range a.Routes() {
+		path := r.Host + r.Path
+		if r.Deprecated {
+			path += " (deprecated)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Method, path, r.Name, r.Handler)
+	}
+	tw.Flush()
+}
+
+func (a *App) startDev() {
+	if a.dev == nil || IsChild() {
+		return
+	}
+	a.printRoutes(os.Stdout)
+	a.dev.start()
+}
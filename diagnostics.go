@@ -0,0 +1,61 @@
+package fastrest
+
+import (
+	rtmetrics "runtime/metrics"
+	"sync/atomic"
+)
+
+var allocSampleNames = []string{
+	"/gc/heap/allocs:bytes",
+	"/gc/heap/allocs:objects",
+}
+
+type allocSampler struct {
+	rate    uint64
+	counter uint64
+}
+
+type allocSample struct {
+	samples []rtmetrics.Sample
+}
+
+func newAllocSampler(rate int) *allocSampler {
+	if rate <= 0 {
+		rate = 100
+	}
+	return &allocSampler{rate: uint64(rate)}
+}
+
+func (s *allocSampler) sample() bool {
+	return atomic.AddUint64(&s.counter, 1)%s.rate == 0
+}
+
+func startAllocSample() *allocSample {
+	samples := make([]rtmetrics.Sample, len(allocSampleNames))
+	for i, name := range allocSampleNames {
+		samples[i].Name = name
+	}
+	rtmetrics.Read(samples)
+	return &allocSample{samples: samples}
+}
+
+func (a *allocSample) finish() (bytes, objects uint64) {
+	end := make([]rtmetrics.Sample, len(allocSampleNames))
+	for i, name := range allocSampleNames {
+		end[i].Name = name
+	}
+	rtmetrics.Read(end)
+
+	return allocDelta(a.samples[0], end[0]), allocDelta(a.samples[1], end[1])
+}
+
+func allocDelta(start, end rtmetrics.Sample) uint64 {
+	if start.Value.Kind() != rtmetrics.KindUint64 || end.Value.Kind() != rtmetrics.KindUint64 {
+		return 0
+	}
+	s, e := start.Value.Uint64(), end.Value.Uint64()
+	if e < s {
+		return 0
+	}
+	return e - s
+}
@@ -0,0 +1,87 @@
+package fastrest
+
+import (
+	"errors"
+	"strings"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+type Error struct {
+	Code    int
+	Message string
+	Details interface{}
+	err     error
+}
+
+var (
+	ErrBadRequest         = NewError(constant.StatusBadRequest, "")
+	ErrUnauthorized       = NewError(constant.StatusUnauthorized, "")
+	ErrForbidden          = NewError(constant.StatusForbidden, "")
+	ErrNotFound           = NewError(constant.StatusNotFound, "")
+	ErrConflict           = NewError(constant.StatusConflict, "")
+	ErrGone               = NewError(constant.StatusGone, "")
+	ErrUnprocessable      = NewError(constant.StatusUnprocessableEntity, "")
+	ErrTooManyRequests    = NewError(constant.StatusTooManyRequests, "")
+	ErrInternal           = NewError(constant.StatusInternalServerError, "")
+	ErrServiceUnavailable = NewError(constant.StatusServiceUnavailable, "")
+)
+
+func NewError(status int, msg string) *Error {
+	if msg == "" {
+		msg = statusMessage(status)
+	}
+	return &Error{Code: status, Message: msg}
+}
+
+func (e *Error) Error() string {
+	if e.err != nil {
+		return e.Message + ": " + e.err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func (e *Error) StatusCode() int {
+	return e.Code
+}
+
+func (e *Error) WithDetails(details interface{}) *Error {
+	clone := *e
+	clone.Details = details
+	return &clone
+}
+
+func (e *Error) Wrap(err error) *Error {
+	clone := *e
+	clone.err = err
+	return &clone
+}
+
+type statusCoder interface {
+	StatusCode() int
+}
+
+func writeError(c *context.Ctx, err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		c.SendErrorDetails(e.Code, e.Message, e.Details)
+		return e.Code
+	}
+
+	var sc statusCoder
+	written := c.Response.StatusCode() != constant.StatusOK || len(c.Response.Body()) > 0
+	status := context.ErrorStatus(c, err)
+	if errors.As(err, &sc) || !written {
+		c.SendError(status, statusMessage(status))
+	}
+	return status
+}
+
+func statusMessage(status int) string {
+	return strings.ToLower(constant.StatusText(status))
+}
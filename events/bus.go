@@ -0,0 +1,184 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fastrest/metrics"
+	"fastrest/pkg/clock"
+	"fastrest/pkg/logging"
+	"fastrest/worker"
+)
+
+var ErrBusClosed = errors.New("events: bus is shut down")
+
+type Event struct {
+	ID      uint64
+	Topic   string
+	Payload interface{}
+	Time    time.Time
+}
+
+type Handler func(ctx context.Context, e Event) error
+
+type Config struct {
+	Pool    *worker.Pool
+	Logger  logging.Logger
+	Metrics *metrics.Metrics
+	Clock   clock.Clock
+}
+
+type Bus struct {
+	cfg     Config
+	ownPool bool
+	seq     atomic.Uint64
+	nextSub uint64
+	mu      sync.RWMutex
+	subs    []*subscription
+	closed  bool
+}
+
+type subscription struct {
+	id      uint64
+	pattern string
+	handler Handler
+}
+
+func New(cfg *Config) *Bus {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Logger == nil {
+		c.Logger = logging.NewLogger()
+	}
+	if c.Clock == nil {
+		c.Clock = clock.System
+	}
+	b := &Bus{cfg: c}
+	if c.Pool == nil {
+		b.cfg.Pool = worker.New(&worker.Config{Logger: c.Logger, Metrics: c.Metrics, Clock: c.Clock})
+		b.ownPool = true
+	}
+	return b
+}
+
+func (b *Bus) Subscribe(topic string, handler Handler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextSub++
+	id := b.nextSub
+	b.subs = append(b.subs, &subscription{id: id, pattern: topic, handler: handler})
+	b.cfg.Metrics.Gauge("event_subscribers", "topic", topic).Inc()
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, s := range b.subs {
+			if s.id == id {
+				b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+				b.cfg.Metrics.Gauge("event_subscribers", "topic", topic).Dec()
+				return
+			}
+		}
+	}
+}
+
+func (b *Bus) Publish(topic string, payload interface{}) error {
+	e, subs, err := b.prepare(topic, payload)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, s := range subs {
+		handler := s.handler
+		task := func(ctx context.Context) error {
+			return handler(ctx, e)
+		}
+		if err := b.cfg.Pool.Submit("event:"+topic, task); err != nil {
+			b.cfg.Metrics.Counter("events_dropped_total", "topic", topic).Inc()
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Bus) PublishSync(ctx context.Context, topic string, payload interface{}) error {
+	e, subs, err := b.prepare(topic, payload)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, s := range subs {
+		if err := s.handler(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Bus) prepare(topic string, payload interface{}) (Event, []*subscription, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return Event{}, nil, ErrBusClosed
+	}
+	e := Event{
+		ID:      b.seq.Add(1),
+		Topic:   topic,
+		Payload: payload,
+		Time:    b.cfg.Clock.Now(),
+	}
+	var subs []*subscription
+	for _, s := range b.subs {
+		if Match(s.pattern, topic) {
+			subs = append(subs, s)
+		}
+	}
+	b.cfg.Metrics.Counter("events_published_total", "topic", topic).Inc()
+	return e, subs, nil
+}
+
+func (b *Bus) Topics() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	seen := make(map[string]bool, len(b.subs))
+	topics := make([]string, 0, len(b.subs))
+	for _, s := range b.subs {
+		if !seen[s.pattern] {
+			seen[s.pattern] = true
+			topics = append(topics, s.pattern)
+		}
+	}
+	return topics
+}
+
+func (b *Bus) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	if b.ownPool {
+		return b.cfg.Pool.Shutdown(ctx)
+	}
+	return nil
+}
+
+func Match(pattern, topic string) bool {
+	if pattern == "*" || pattern == topic {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(topic, prefix)
+	}
+	return false
+}
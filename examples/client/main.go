@@ -3,12 +3,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"fastrest/client"
 )
 
+var ctx = context.Background()
+
 func main() {
 	fmt.Println("╔═══════════════════════════════════════════════════════════════╗")
 	fmt.Println("║           FastREST Client Test Suite                          ║")
@@ -39,7 +42,7 @@ func testPublicEndpoints(c *client.Client) {
 	fmt.Println("└───────────────────────────────────────────────────────────────┘")
 
 	log.Println("[TEST] GET / - Root endpoint")
-	resp, err := c.Get("/")
+	resp, err := c.Get(ctx, "/")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -47,7 +50,7 @@ func testPublicEndpoints(c *client.Client) {
 	}
 
 	log.Println("[TEST] GET /ping - Ping endpoint")
-	resp, err = c.Get("/ping")
+	resp, err = c.Get(ctx, "/ping")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -55,7 +58,7 @@ func testPublicEndpoints(c *client.Client) {
 	}
 
 	log.Println("[TEST] GET /health - Health check")
-	resp, err = c.Get("/health")
+	resp, err = c.Get(ctx, "/health")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -63,7 +66,7 @@ func testPublicEndpoints(c *client.Client) {
 	}
 
 	log.Println("[TEST] GET /health/live - Liveness probe")
-	resp, err = c.Get("/health/live")
+	resp, err = c.Get(ctx, "/health/live")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -71,7 +74,7 @@ func testPublicEndpoints(c *client.Client) {
 	}
 
 	log.Println("[TEST] GET /health/ready - Readiness probe")
-	resp, err = c.Get("/health/ready")
+	resp, err = c.Get(ctx, "/health/ready")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -79,7 +82,7 @@ func testPublicEndpoints(c *client.Client) {
 	}
 
 	log.Println("[TEST] GET /external/info - External info")
-	resp, err = c.Get("/external/info")
+	resp, err = c.Get(ctx, "/external/info")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -95,7 +98,7 @@ func testUsersAPI(c *client.Client) {
 	fmt.Println("└───────────────────────────────────────────────────────────────┘")
 
 	log.Println("[TEST] GET /users - List all users")
-	resp, err := c.Get("/users")
+	resp, err := c.Get(ctx, "/users")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -103,7 +106,7 @@ func testUsersAPI(c *client.Client) {
 	}
 
 	log.Println("[TEST] GET /users/1 - Get user by ID")
-	resp, err = c.Get("/users/1")
+	resp, err = c.Get(ctx, "/users/1")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -111,7 +114,7 @@ func testUsersAPI(c *client.Client) {
 	}
 
 	log.Println("[TEST] POST /users - Create new user")
-	resp, err = c.Post("/users", map[string]interface{}{
+	resp, err = c.Post(ctx, "/users", map[string]interface{}{
 		"name":  "New User",
 		"email": "newuser@example.com",
 	})
@@ -122,7 +125,7 @@ func testUsersAPI(c *client.Client) {
 	}
 
 	log.Println("[TEST] PUT /users/1 - Update user")
-	resp, err = c.Put("/users/1", map[string]interface{}{
+	resp, err = c.Put(ctx, "/users/1", map[string]interface{}{
 		"name":  "Updated User",
 		"email": "updated@example.com",
 	})
@@ -133,7 +136,7 @@ func testUsersAPI(c *client.Client) {
 	}
 
 	log.Println("[TEST] PATCH /users/1 - Partial update user")
-	resp, err = c.Patch("/users/1", map[string]interface{}{
+	resp, err = c.Patch(ctx, "/users/1", map[string]interface{}{
 		"email": "patched@example.com",
 	})
 	if err != nil {
@@ -143,7 +146,7 @@ func testUsersAPI(c *client.Client) {
 	}
 
 	log.Println("[TEST] DELETE /users/1 - Delete user")
-	resp, err = c.Delete("/users/1")
+	resp, err = c.Delete(ctx, "/users/1")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -160,7 +163,7 @@ func testBasicAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/basic/profile - Valid credentials (admin)")
 	c := client.New(baseURL, client.WithBasicAuth("admin", "password123"))
-	resp, err := c.Get("/auth/basic/profile")
+	resp, err := c.Get(ctx, "/auth/basic/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -169,7 +172,7 @@ func testBasicAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/basic/profile - Valid credentials (user)")
 	c = client.New(baseURL, client.WithBasicAuth("user", "user123"))
-	resp, err = c.Get("/auth/basic/profile")
+	resp, err = c.Get(ctx, "/auth/basic/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -178,7 +181,7 @@ func testBasicAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/basic/profile - Invalid credentials")
 	c = client.New(baseURL, client.WithBasicAuth("wrong", "wrong"))
-	resp, err = c.Get("/auth/basic/profile")
+	resp, err = c.Get(ctx, "/auth/basic/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -187,7 +190,7 @@ func testBasicAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/basic/profile - No credentials")
 	c = client.New(baseURL)
-	resp, err = c.Get("/auth/basic/profile")
+	resp, err = c.Get(ctx, "/auth/basic/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -204,7 +207,7 @@ func testBearerAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/bearer/profile - Valid token")
 	c := client.New(baseURL, client.WithBearerToken("secret-token-123"))
-	resp, err := c.Get("/auth/bearer/profile")
+	resp, err := c.Get(ctx, "/auth/bearer/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -213,7 +216,7 @@ func testBearerAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/bearer/profile - Another valid token")
 	c = client.New(baseURL, client.WithBearerToken("another-token"))
-	resp, err = c.Get("/auth/bearer/profile")
+	resp, err = c.Get(ctx, "/auth/bearer/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -222,7 +225,7 @@ func testBearerAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/bearer/profile - Invalid token")
 	c = client.New(baseURL, client.WithBearerToken("invalid-token"))
-	resp, err = c.Get("/auth/bearer/profile")
+	resp, err = c.Get(ctx, "/auth/bearer/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -231,7 +234,7 @@ func testBearerAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/bearer/profile - No token")
 	c = client.New(baseURL)
-	resp, err = c.Get("/auth/bearer/profile")
+	resp, err = c.Get(ctx, "/auth/bearer/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -248,7 +251,7 @@ func testAPIKeyAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/apikey/profile - Valid API key")
 	c := client.New(baseURL, client.WithAPIKey("api-key-xyz"))
-	resp, err := c.Get("/auth/apikey/profile")
+	resp, err := c.Get(ctx, "/auth/apikey/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -257,7 +260,7 @@ func testAPIKeyAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/apikey/profile - Another valid API key")
 	c = client.New(baseURL, client.WithAPIKey("api-key-testing"))
-	resp, err = c.Get("/auth/apikey/profile")
+	resp, err = c.Get(ctx, "/auth/apikey/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -266,7 +269,7 @@ func testAPIKeyAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/apikey/profile - Invalid API key")
 	c = client.New(baseURL, client.WithAPIKey("wrong-key"))
-	resp, err = c.Get("/auth/apikey/profile")
+	resp, err = c.Get(ctx, "/auth/apikey/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -275,7 +278,7 @@ func testAPIKeyAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/apikey/profile - No API key")
 	c = client.New(baseURL)
-	resp, err = c.Get("/auth/apikey/profile")
+	resp, err = c.Get(ctx, "/auth/apikey/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -292,7 +295,7 @@ func testCombinedAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/any/profile - With Basic Auth")
 	c := client.New(baseURL, client.WithBasicAuth("admin", "password123"))
-	resp, err := c.Get("/auth/any/profile")
+	resp, err := c.Get(ctx, "/auth/any/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -301,7 +304,7 @@ func testCombinedAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/any/profile - With Bearer Token")
 	c = client.New(baseURL, client.WithBearerToken("secret-token-123"))
-	resp, err = c.Get("/auth/any/profile")
+	resp, err = c.Get(ctx, "/auth/any/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -310,7 +313,7 @@ func testCombinedAuth(baseURL string) {
 
 	log.Println("[TEST] GET /auth/any/profile - With API Key")
 	c = client.New(baseURL, client.WithAPIKey("api-key-xyz"))
-	resp, err = c.Get("/auth/any/profile")
+	resp, err = c.Get(ctx, "/auth/any/profile")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -326,7 +329,7 @@ func testMetrics(c *client.Client) {
 	fmt.Println("└───────────────────────────────────────────────────────────────┘")
 
 	log.Println("[TEST] GET /metrics - Prometheus format")
-	resp, err := c.Get("/metrics")
+	resp, err := c.Get(ctx, "/metrics")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -334,7 +337,7 @@ func testMetrics(c *client.Client) {
 	}
 
 	log.Println("[TEST] GET /metrics/json - JSON format")
-	resp, err = c.Get("/metrics/json")
+	resp, err = c.Get(ctx, "/metrics/json")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -350,7 +353,7 @@ func testErrorCases(c *client.Client, baseURL string) {
 	fmt.Println("└───────────────────────────────────────────────────────────────┘")
 
 	log.Println("[TEST] GET /notfound - 404 Not Found")
-	resp, err := c.Get("/notfound")
+	resp, err := c.Get(ctx, "/notfound")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
@@ -359,7 +362,7 @@ func testErrorCases(c *client.Client, baseURL string) {
 
 	log.Println("[TEST] POST /users - Invalid JSON body")
 	invalidClient := client.New(baseURL, client.WithHeader("Content-Type", "application/json"))
-	resp, err = invalidClient.Post("/users", "invalid json")
+	resp, err = invalidClient.Post(ctx, "/users", "invalid json")
 	if err != nil {
 		log.Printf("[FAIL] Error: %v\n", err)
 	} else {
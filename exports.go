@@ -1,29 +1,104 @@
 package fastrest
 
 import (
+	"io"
+	"time"
+
 	"fastrest/constant"
 	"fastrest/context"
 	"fastrest/metrics"
 	"fastrest/middlewares"
+	"fastrest/openapi"
+	"fastrest/pkg/clock"
 	"fastrest/pkg/logging"
+	"fastrest/proxy"
+	"fastrest/worker"
 )
 
 type Ctx = context.Ctx
 type Handler = context.Handler
 type Middleware = context.Middleware
 type AuthInfo = context.AuthInfo
+type BindError = context.BindError
+type MediaTypeError = context.MediaTypeError
+type DecodeOptions = context.DecodeOptions
+type BodyOption = context.BodyOption
+type ResponseWrapper = context.ResponseWrapper
+type ErrorBody = context.ErrorBody
+type Page = context.Page
+type PageDefaults = context.PageDefaults
+type Pagination = context.Pagination
+type PagedResponse = context.PagedResponse
+type Problem = context.Problem
+type Renderer = context.Renderer
+type FeatureChecker = context.FeatureChecker
+type LocalKey[T any] = context.Key[T]
+
+const (
+	RequestIDKey = context.RequestIDKey
+	AuthKey      = context.AuthKey
+	TraceIDKey   = context.TraceIDKey
+	SpanIDKey    = context.SpanIDKey
+	TenantKey    = context.TenantKey
+
+	AllowedMethodsKey = context.AllowedMethodsKey
+	RoutePatternKey   = context.RoutePatternKey
+	CacheTagsKey      = context.CacheTagsKey
+)
+
+type TaskStatus = worker.TaskStatus
+
+type Clock = clock.Clock
+type MockClock = clock.Mock
 
 type Logger = logging.Logger
 type ConsoleLogger = logging.ConsoleLogger
+type JSONLogger = logging.JSONLogger
+type RotateConfig = logging.RotateConfig
+type RotatingFile = logging.RotatingFile
 type LogLevel = logging.LogLevel
 
 type Metrics = metrics.Metrics
 type MetricsJSON = metrics.MetricsJSON
+type AllocJSON = metrics.AllocJSON
+type RequestKey = metrics.RequestKey
+type Counter = metrics.Counter
+type Gauge = metrics.Gauge
+type Histogram = metrics.Histogram
+type RouteKey = metrics.RouteKey
+type ErrorKey = metrics.ErrorKey
+type MetricsSnapshot = metrics.Snapshot
+type MetricsRecorder = metrics.Recorder
+
+type ContractReporter = middlewares.ContractReporter
+type AccessLogConfig = middlewares.AccessLogConfig
+type RequestLoggerConfig = middlewares.RequestLoggerConfig
+type DumpConfig = middlewares.DumpConfig
+type RedactConfig = middlewares.RedactConfig
+type Redactor = middlewares.Redactor
+type CacheStore = middlewares.CacheStore
+type CachedResponse = middlewares.CachedResponse
+type CacheOption = middlewares.CacheOption
+type CacheInvalidator = middlewares.CacheInvalidator
+type EvictNotifier = middlewares.EvictNotifier
+type FeatureOption = middlewares.FeatureOption
+type LRUStore = middlewares.LRUStore
+type IdempotencyStore = middlewares.IdempotencyStore
+type IdempotencyRecord = middlewares.IdempotencyRecord
+type IdempotencyOption = middlewares.IdempotencyOption
+type MemoryIdempotencyStore = middlewares.MemoryIdempotencyStore
+type CORSConfig = middlewares.CORSConfig
+type SecureOption = middlewares.SecureOption
+type ConcurrencyOption = middlewares.ConcurrencyOption
 
 type AuthConfig = middlewares.AuthConfig
 type BasicAuthValidator = middlewares.BasicAuthValidator
 type BearerAuthValidator = middlewares.BearerAuthValidator
 type APIKeyValidator = middlewares.APIKeyValidator
+type HMACConfig = middlewares.HMACConfig
+type HMACKeyFunc = middlewares.HMACKeyFunc
+type NonceStore = middlewares.NonceStore
+type MemoryNonceStore = middlewares.MemoryNonceStore
 
 const (
 	LevelDebug = logging.LevelDebug
@@ -110,6 +185,18 @@ func NewLogger() *ConsoleLogger {
 	return logging.NewLogger()
 }
 
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return logging.NewJSONLogger(w)
+}
+
+func NewRotatingFile(cfg *RotateConfig) (*RotatingFile, error) {
+	return logging.NewRotatingFile(cfg)
+}
+
+func WithFields(logger Logger, fields ...interface{}) Logger {
+	return logging.With(logger, fields...)
+}
+
 func NewMetricsLogger(logger Logger, m *Metrics) *logging.MetricsLogger {
 	return logging.NewMetricsLogger(logger, m)
 }
@@ -118,6 +205,10 @@ func NewMetrics() *Metrics {
 	return metrics.New()
 }
 
+func NewMetricsWithBuckets(buckets []float64) *Metrics {
+	return metrics.NewWithBuckets(buckets)
+}
+
 func NewAuthConfig() *AuthConfig {
 	return middlewares.NewAuthConfig()
 }
@@ -138,6 +229,202 @@ func Auth(config *AuthConfig) Middleware {
 	return middlewares.Auth(config)
 }
 
+func HMACAuth(cfg *HMACConfig) Middleware {
+	return middlewares.HMACAuth(cfg)
+}
+
+func HMACKeys(keys map[string][]byte) HMACKeyFunc {
+	return middlewares.HMACKeys(keys)
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return middlewares.NewMemoryNonceStore()
+}
+
 func RequestLogger() Middleware {
 	return middlewares.RequestLogger()
 }
+
+func RequestLoggerWithConfig(cfg *RequestLoggerConfig) Middleware {
+	return middlewares.RequestLoggerWithConfig(cfg)
+}
+
+func AccessLog(cfg *AccessLogConfig) Middleware {
+	return middlewares.AccessLog(cfg)
+}
+
+func Dump(cfg *DumpConfig) Middleware {
+	return middlewares.Dump(cfg)
+}
+
+func NewRedactor(cfg *RedactConfig) *Redactor {
+	return middlewares.NewRedactor(cfg)
+}
+
+func Contract(doc *openapi.Document, reporter ContractReporter) Middleware {
+	return middlewares.Contract(doc, reporter)
+}
+
+func RequestID() Middleware {
+	return middlewares.RequestID()
+}
+
+func CORS(cfg *CORSConfig) Middleware {
+	return middlewares.CORS(cfg)
+}
+
+func RequireScopes(scopes ...string) Middleware {
+	return middlewares.RequireScopes(scopes...)
+}
+
+func RequireRoles(roles ...string) Middleware {
+	return middlewares.RequireRoles(roles...)
+}
+
+func RequireFeature(name string, opts ...FeatureOption) Middleware {
+	return middlewares.RequireFeature(name, opts...)
+}
+
+func WithFeatureStatus(status int) FeatureOption {
+	return middlewares.WithFeatureStatus(status)
+}
+
+func Secure(opts ...SecureOption) Middleware {
+	return middlewares.Secure(opts...)
+}
+
+func SecureHeader(name, value string) SecureOption {
+	return middlewares.SecureHeader(name, value)
+}
+
+func RequireContentType(types ...string) Middleware {
+	return middlewares.RequireContentType(types...)
+}
+
+func RequireAccept(types ...string) Middleware {
+	return middlewares.RequireAccept(types...)
+}
+
+func Timeout(d time.Duration) Middleware {
+	return middlewares.Timeout(d)
+}
+
+func Concurrency(max, queueLen int, timeout time.Duration, opts ...ConcurrencyOption) Middleware {
+	return middlewares.Concurrency(max, queueLen, timeout, opts...)
+}
+
+func WithConcurrencyName(name string) ConcurrencyOption {
+	return middlewares.WithConcurrencyName(name)
+}
+
+func WithConcurrencyMetrics(m *Metrics) ConcurrencyOption {
+	return middlewares.WithConcurrencyMetrics(m)
+}
+
+func WithRetryAfter(d time.Duration) ConcurrencyOption {
+	return middlewares.WithRetryAfter(d)
+}
+
+func BodyLimit(limit int) Middleware {
+	return middlewares.BodyLimit(limit)
+}
+
+func Cache(ttl time.Duration, opts ...CacheOption) Middleware {
+	return middlewares.Cache(ttl, opts...)
+}
+
+func NewLRUStore(capacity int) *LRUStore {
+	return middlewares.NewLRUStore(capacity)
+}
+
+func WithCacheStore(store CacheStore) CacheOption {
+	return middlewares.WithCacheStore(store)
+}
+
+func WithCacheKey(key func(c *Ctx) string) CacheOption {
+	return middlewares.WithCacheKey(key)
+}
+
+func WithCacheBypassHeaders(headers ...string) CacheOption {
+	return middlewares.WithCacheBypassHeaders(headers...)
+}
+
+func WithCacheInvalidator(inv *CacheInvalidator) CacheOption {
+	return middlewares.WithCacheInvalidator(inv)
+}
+
+func NewCacheInvalidator() *CacheInvalidator {
+	return middlewares.NewCacheInvalidator()
+}
+
+func DefaultCacheInvalidator() *CacheInvalidator {
+	return middlewares.DefaultCacheInvalidator()
+}
+
+func Idempotency(ttl time.Duration, opts ...IdempotencyOption) Middleware {
+	return middlewares.Idempotency(ttl, opts...)
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return middlewares.NewMemoryIdempotencyStore()
+}
+
+func WithIdempotencyStore(store IdempotencyStore) IdempotencyOption {
+	return middlewares.WithIdempotencyStore(store)
+}
+
+func WithIdempotencyScope(scope func(c *Ctx) string) IdempotencyOption {
+	return middlewares.WithIdempotencyScope(scope)
+}
+
+func NewProblem(status int, typ, title, detail string) *Problem {
+	return context.NewProblem(status, typ, title, detail)
+}
+
+func Local[T any](c *Ctx, key string) (T, bool) {
+	return context.Local[T](c, key)
+}
+
+func LocalOr[T any](c *Ctx, key string, fallback T) T {
+	return context.LocalOr(c, key, fallback)
+}
+
+var ErrNoAuth = context.ErrNoAuth
+
+var ErrBodyTooLarge = context.ErrBodyTooLarge
+
+func DisallowUnknownFields() BodyOption {
+	return context.DisallowUnknownFields()
+}
+
+func UseNumber() BodyOption {
+	return context.UseNumber()
+}
+
+func MaxDepth(depth int) BodyOption {
+	return context.MaxDepth(depth)
+}
+
+func MaxBodySize(size int) BodyOption {
+	return context.MaxBodySize(size)
+}
+
+func Claim[T any](auth *AuthInfo, name string) (T, bool) {
+	return context.Claim[T](auth, name)
+}
+
+func MarshalScoped(v interface{}, scopes []string) ([]byte, error) {
+	return context.MarshalScoped(v, scopes)
+}
+
+func Proxy(targets ...string) Handler {
+	p, err := proxy.New(&proxy.Config{Targets: targets})
+	if err != nil {
+		panic(err)
+	}
+	return p.Handler()
+}
+
+func NewMockClock(now time.Time) *MockClock {
+	return clock.NewMock(now)
+}
@@ -0,0 +1,39 @@
+package featureflags
+
+import (
+	"fastrest/context"
+)
+
+type Rule func(c *context.Ctx, name string) (enabled bool, ok bool)
+
+type Flags struct {
+	providers []Provider
+	rules     []Rule
+}
+
+func New(providers ...Provider) *Flags {
+	return &Flags{providers: providers}
+}
+
+func (f *Flags) WithRule(rule Rule) *Flags {
+	f.rules = append(f.rules, rule)
+	return f
+}
+
+func (f *Flags) Enabled(name string) bool {
+	for _, p := range f.providers {
+		if enabled, ok := p.Lookup(name); ok {
+			return enabled
+		}
+	}
+	return false
+}
+
+func (f *Flags) FeatureEnabled(c *context.Ctx, name string) bool {
+	for _, rule := range f.rules {
+		if enabled, ok := rule(c, name); ok {
+			return enabled
+		}
+	}
+	return f.Enabled(name)
+}
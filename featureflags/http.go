@@ -0,0 +1,130 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type HTTPConfig struct {
+	Interval time.Duration
+	Client   *http.Client
+	Headers  map[string]string
+	OnError  func(err error)
+}
+
+type HTTPPoller struct {
+	url     string
+	cfg     HTTPConfig
+	mu      sync.RWMutex
+	flags   map[string]bool
+	etag    string
+	updated time.Time
+	stop    chan struct{}
+	once    sync.Once
+}
+
+func NewHTTPPoller(url string, cfg *HTTPConfig) *HTTPPoller {
+	c := HTTPConfig{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Interval <= 0 {
+		c.Interval = 30 * time.Second
+	}
+	if c.Client == nil {
+		c.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPPoller{url: url, cfg: c, flags: map[string]bool{}, stop: make(chan struct{})}
+}
+
+func (p *HTTPPoller) Lookup(name string) (bool, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	enabled, ok := p.flags[name]
+	return enabled, ok
+}
+
+func (p *HTTPPoller) Updated() time.Time {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.updated
+}
+
+func (p *HTTPPoller) Start(ctx context.Context) error {
+	if err := p.Refresh(ctx); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(p.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := p.Refresh(context.Background()); err != nil && p.cfg.OnError != nil {
+					p.cfg.OnError(err)
+				}
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (p *HTTPPoller) Stop() {
+	p.once.Do(func() { close(p.stop) })
+}
+
+func (p *HTTPPoller) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range p.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	p.mu.RLock()
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	p.mu.RUnlock()
+
+	resp, err := p.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("featureflags: fetch %s: %w", p.url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		p.mu.Lock()
+		p.updated = time.Now()
+		p.mu.Unlock()
+		return nil
+	case http.StatusOK:
+	default:
+		io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("featureflags: fetch %s: unexpected status %d", p.url, resp.StatusCode)
+	}
+
+	var flags map[string]bool
+	if err := json.NewDecoder(resp.Body).Decode(&flags); err != nil {
+		return fmt.Errorf("featureflags: decode %s: %w", p.url, err)
+	}
+	if flags == nil {
+		flags = map[string]bool{}
+	}
+
+	p.mu.Lock()
+	p.flags = flags
+	p.etag = resp.Header.Get("ETag")
+	p.updated = time.Now()
+	p.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,80 @@
+package featureflags
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Provider interface {
+	Lookup(name string) (enabled bool, ok bool)
+}
+
+type ProviderFunc func(name string) (bool, bool)
+
+func (f ProviderFunc) Lookup(name string) (bool, bool) {
+	return f(name)
+}
+
+type StaticProvider struct {
+	mu    sync.RWMutex
+	flags map[string]bool
+}
+
+func NewStatic(flags map[string]bool) *StaticProvider {
+	p := &StaticProvider{flags: make(map[string]bool, len(flags))}
+	for name, enabled := range flags {
+		p.flags[name] = enabled
+	}
+	return p
+}
+
+func (p *StaticProvider) Lookup(name string) (bool, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	enabled, ok := p.flags[name]
+	return enabled, ok
+}
+
+func (p *StaticProvider) Set(name string, enabled bool) {
+	p.mu.Lock()
+	p.flags[name] = enabled
+	p.mu.Unlock()
+}
+
+func (p *StaticProvider) Delete(name string) {
+	p.mu.Lock()
+	delete(p.flags, name)
+	p.mu.Unlock()
+}
+
+type EnvProvider struct {
+	prefix string
+}
+
+func NewEnv(prefix string) *EnvProvider {
+	return &EnvProvider{prefix: prefix}
+}
+
+func (p *EnvProvider) Lookup(name string) (bool, bool) {
+	raw, ok := os.LookupEnv(p.Key(name))
+	if !ok {
+		return false, false
+	}
+	enabled, err := strconv.ParseBool(strings.TrimSpace(raw))
+	if err != nil {
+		return false, false
+	}
+	return enabled, true
+}
+
+func (p *EnvProvider) Key(name string) string {
+	key := strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	return p.prefix + strings.ToUpper(key)
+}
@@ -0,0 +1,272 @@
+package grpcbridge
+
+import (
+	stdctx "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"fastrest"
+	"fastrest/constant"
+	"fastrest/context"
+	"fastrest/metrics"
+)
+
+type Invoker interface {
+	Invoke(ctx stdctx.Context, method string, req, resp interface{}) error
+}
+
+type InvokerFunc func(ctx stdctx.Context, method string, req, resp interface{}) error
+
+func (f InvokerFunc) Invoke(ctx stdctx.Context, method string, req, resp interface{}) error {
+	return f(ctx, method, req, resp)
+}
+
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type Metadata map[string][]string
+
+type metadataKey struct{}
+
+func MetadataFromContext(ctx stdctx.Context) Metadata {
+	md, _ := ctx.Value(metadataKey{}).(Metadata)
+	return md
+}
+
+var DefaultForwardHeaders = []string{
+	"Authorization", "X-Request-ID", "Traceparent", "Tracestate", "Accept-Language",
+}
+
+type Binding struct {
+	HTTPMethod  string
+	Path        string
+	Method      string
+	Body        string
+	NewRequest  func() interface{}
+	NewResponse func() interface{}
+}
+
+type Config struct {
+	Name           string
+	Invoker        Invoker
+	Codec          Codec
+	Bindings       []Binding
+	ForwardHeaders []string
+	Metadata       func(c *context.Ctx, md Metadata)
+	ErrorCode      func(err error) (Code, string)
+	Middleware     []context.Middleware
+	Timeout        time.Duration
+	Metrics        *metrics.Metrics
+}
+
+type Bridge struct {
+	fastrest.BaseModule
+	cfg Config
+}
+
+func New(cfg *Config) *Bridge {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Name == "" {
+		c.Name = "grpcbridge"
+	}
+	if c.Codec == nil {
+		c.Codec = JSONCodec{}
+	}
+	if c.ForwardHeaders == nil {
+		c.ForwardHeaders = DefaultForwardHeaders
+	}
+	if c.ErrorCode == nil {
+		c.ErrorCode = defaultErrorCode
+	}
+	return &Bridge{cfg: c}
+}
+
+func (b *Bridge) Name() string {
+	return b.cfg.Name
+}
+
+func (b *Bridge) Routes(r *fastrest.Router) {
+	for _, binding := range b.cfg.Bindings {
+		service, method := splitMethod(binding.Method)
+		route := r.Handle(binding.HTTPMethod, binding.Path, b.Handler(binding)).Summary(service + "." + method)
+		if service != "" {
+			route.Tags(service)
+		}
+	}
+}
+
+func (b *Bridge) Middlewares() []fastrest.Middleware {
+	return b.cfg.Middleware
+}
+
+func (b *Bridge) OnStop(ctx stdctx.Context) error {
+	if closer, ok := b.cfg.Invoker.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (b *Bridge) Handler(binding Binding) context.Handler {
+	if binding.Body == "" && hasBody(binding.HTTPMethod) {
+		binding.Body = "*"
+	}
+	calls := func(code Code) *metrics.Counter {
+		return b.cfg.Metrics.Counter("grpc_bridge_calls_total", "method", binding.Method, "code", code.String())
+	}
+	latency := b.cfg.Metrics.Histogram("grpc_bridge_call_duration_seconds", "method", binding.Method)
+
+	return func(c *context.Ctx) error {
+		req := binding.NewRequest()
+		payload, err := requestJSON(c, binding.Body, requestFields(req))
+		if err != nil {
+			return fastrest.NewError(constant.StatusBadRequest, "invalid request").Wrap(err)
+		}
+		if err := b.cfg.Codec.Unmarshal(payload, req); err != nil {
+			return fastrest.NewError(constant.StatusBadRequest, "invalid request").Wrap(err)
+		}
+		resp := binding.NewResponse()
+
+		ctx := stdctx.WithValue(c.Context(), metadataKey{}, b.metadata(c))
+		if b.cfg.Timeout > 0 {
+			var cancel stdctx.CancelFunc
+			ctx, cancel = stdctx.WithTimeout(ctx, b.cfg.Timeout)
+			defer cancel()
+		}
+
+		start := c.Now()
+		err = b.cfg.Invoker.Invoke(ctx, binding.Method, req, resp)
+		latency.Observe(c.Clock().Since(start).Seconds())
+		if err != nil {
+			code, msg := b.cfg.ErrorCode(err)
+			calls(code).Inc()
+			return fastrest.NewError(HTTPStatus(code), msg).
+				WithDetails(map[string]interface{}{"grpc_code": code.String(), "method": binding.Method}).
+				Wrap(err)
+		}
+		calls(OK).Inc()
+
+		data, err := b.cfg.Codec.Marshal(resp)
+		if err != nil {
+			return fmt.Errorf("grpcbridge: encode %s response: %w", binding.Method, err)
+		}
+		c.Response.Header.SetContentType("application/json")
+		c.Response.SetStatusCode(constant.StatusOK)
+		c.Response.SetBody(data)
+		return nil
+	}
+}
+
+func (b *Bridge) metadata(c *context.Ctx) Metadata {
+	md := Metadata{}
+	for _, h := range b.cfg.ForwardHeaders {
+		if v := c.Get(h); v != "" {
+			md[strings.ToLower(h)] = []string{v}
+		}
+	}
+	if id := c.RequestID(); id != "" {
+		md["x-request-id"] = []string{id}
+	}
+	if ip := c.IP(); ip != "" {
+		md["x-forwarded-for"] = []string{ip}
+	}
+	if auth, err := c.MustAuth(); err == nil && auth.Subject != "" {
+		md["x-auth-subject"] = []string{auth.Subject}
+	}
+	if tenant := c.Tenant(); tenant != "" {
+		md["x-tenant-id"] = []string{tenant}
+	}
+	if b.cfg.Metadata != nil {
+		b.cfg.Metadata(c, md)
+	}
+	return md
+}
+
+func requestJSON(c *context.Ctx, body string, types map[string]reflect.Type) ([]byte, error) {
+	fields := map[string]interface{}{}
+	raw := c.Request.Body()
+	if body != "" && len(strings.TrimSpace(string(raw))) > 0 {
+		if body == "*" {
+			if err := json.Unmarshal(raw, &fields); err != nil {
+				return nil, err
+			}
+		} else {
+			fields[body] = json.RawMessage(raw)
+		}
+	}
+
+	if body != "*" {
+		for k, v := range c.QueryArgs().All() {
+			key, list := strings.CutSuffix(string(k), "[]")
+			if key == body {
+				continue
+			}
+			switch existing := fields[key].(type) {
+			case nil:
+				if list {
+					fields[key] = []string{string(v)}
+				} else {
+					fields[key] = string(v)
+				}
+			case string:
+				fields[key] = []string{existing, string(v)}
+			case []string:
+				fields[key] = append(existing, string(v))
+			}
+		}
+	}
+	for k, v := range c.Params {
+		if k != "*" {
+			fields[k] = v
+		}
+	}
+	for k, v := range fields {
+		t, ok := types[k]
+		if !ok {
+			continue
+		}
+		if _, raw := v.(json.RawMessage); raw {
+			continue
+		}
+		converted, err := convertField(t, v)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", k, err)
+		}
+		fields[k] = converted
+	}
+	return json.Marshal(fields)
+}
+
+func hasBody(method string) bool {
+	switch strings.ToUpper(method) {
+	case "POST", "PUT", "PATCH":
+		return true
+	}
+	return false
+}
+
+func splitMethod(full string) (service, method string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(full, "/"), "/")
+	if !ok {
+		return "", service
+	}
+	return service, method
+}
@@ -0,0 +1,104 @@
+package grpcbridge
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"fastrest/constant"
+)
+
+type Code uint32
+
+const (
+	OK Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Internal
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
+
+var codeNames = [...]string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND",
+	"ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION", "ABORTED",
+	"OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+func (c Code) String() string {
+	if int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "CODE(" + strconv.Itoa(int(c)) + ")"
+}
+
+func HTTPStatus(c Code) int {
+	switch c {
+	case OK:
+		return constant.StatusOK
+	case Canceled:
+		return 499
+	case InvalidArgument, FailedPrecondition, OutOfRange:
+		return constant.StatusBadRequest
+	case DeadlineExceeded:
+		return constant.StatusGatewayTimeout
+	case NotFound:
+		return constant.StatusNotFound
+	case AlreadyExists, Aborted:
+		return constant.StatusConflict
+	case PermissionDenied:
+		return constant.StatusForbidden
+	case ResourceExhausted:
+		return constant.StatusTooManyRequests
+	case Unimplemented:
+		return constant.StatusNotImplemented
+	case Unavailable:
+		return constant.StatusServiceUnavailable
+	case Unauthenticated:
+		return constant.StatusUnauthorized
+	default:
+		return constant.StatusInternalServerError
+	}
+}
+
+type Error struct {
+	Code    Code
+	Message string
+}
+
+func NewError(code Code, msg string) *Error {
+	return &Error{Code: code, Message: msg}
+}
+
+func (e *Error) Error() string {
+	return "grpc: " + e.Code.String() + ": " + e.Message
+}
+
+func defaultErrorCode(err error) (Code, string) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code, e.Message
+	}
+	var coded interface{ GRPCCode() Code }
+	if errors.As(err, &coded) {
+		return coded.GRPCCode(), err.Error()
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded, "deadline exceeded"
+	case errors.Is(err, context.Canceled):
+		return Canceled, "canceled"
+	}
+	return Unknown, "unknown error"
+}
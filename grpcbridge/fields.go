@@ -0,0 +1,97 @@
+package grpcbridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var fieldTypes sync.Map
+
+func requestFields(req interface{}) map[string]reflect.Type {
+	t := reflect.TypeOf(req)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	if cached, ok := fieldTypes.Load(t); ok {
+		return cached.(map[string]reflect.Type)
+	}
+
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	cached, _ := fieldTypes.LoadOrStore(t, fields)
+	return cached.(map[string]reflect.Type)
+}
+
+func convertField(t reflect.Type, value interface{}) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch v := value.(type) {
+	case string:
+		return convertScalar(t, v)
+	case []string:
+		if t.Kind() != reflect.Slice {
+			return v, nil
+		}
+		out := make([]interface{}, len(v))
+		for i, s := range v {
+			converted, err := convertScalar(t.Elem(), s)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = converted
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+func convertScalar(t reflect.Type, s string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", s)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if _, err := strconv.ParseInt(s, 10, t.Bits()); err != nil {
+			return nil, fmt.Errorf("%q is not a valid %s", s, t.Kind())
+		}
+		return json.Number(s), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, err := strconv.ParseUint(s, 10, t.Bits()); err != nil {
+			return nil, fmt.Errorf("%q is not a valid %s", s, t.Kind())
+		}
+		return json.Number(s), nil
+	case reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(s, t.Bits()); err != nil {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return json.Number(s), nil
+	}
+	return s, nil
+}
@@ -0,0 +1,126 @@
+package fastrest
+
+import (
+	stdctx "context"
+	"errors"
+	"sync"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+type HealthCheckFunc func(ctx stdctx.Context) error
+
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type CheckReport struct {
+	Status string                  `json:"status"`
+	Checks map[string]*CheckResult `json:"checks,omitempty"`
+}
+
+type healthChecks struct {
+	mu     sync.RWMutex
+	names  []string
+	checks map[string]HealthCheckFunc
+}
+
+func newHealthChecks() *healthChecks {
+	return &healthChecks{checks: make(map[string]HealthCheckFunc)}
+}
+
+func (h *healthChecks) add(name string, check HealthCheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = check
+}
+
+func (h *healthChecks) run(timeout time.Duration) *CheckReport {
+	h.mu.RLock()
+	names := append([]string{}, h.names...)
+	checks := make([]HealthCheckFunc, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.RUnlock()
+
+	report := &CheckReport{Status: "ok"}
+	if len(names) == 0 {
+		return report
+	}
+
+	ctx, cancel := stdctx.WithTimeout(stdctx.Background(), timeout)
+	defer cancel()
+
+	results := make([]*CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	report.Checks = make(map[string]*CheckResult, len(names))
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != "ok" {
+			report.Status = "fail"
+		}
+	}
+	return report
+}
+
+func runCheck(ctx stdctx.Context, check HealthCheckFunc) *CheckResult {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- errCheckPanicked
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := &CheckResult{Status: "ok", Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = "fail"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+var errCheckPanicked = errors.New("check panicked")
+
+func (a *App) AddReadinessCheck(name string, check HealthCheckFunc) {
+	a.readiness.add(name, check)
+}
+
+func (a *App) AddLivenessCheck(name string, check HealthCheckFunc) {
+	a.liveness.add(name, check)
+}
+
+func (a *App) writeCheckReport(c *context.Ctx, report *CheckReport) error {
+	status := constant.StatusOK
+	if report.Status != "ok" {
+		status = constant.StatusServiceUnavailable
+	}
+	return c.RawJSON(status, report)
+}
@@ -0,0 +1,185 @@
+package fastrest
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"fastrest/context"
+	"fastrest/pkg/logging"
+)
+
+func (a *App) newHTTP2Server() *http.Server {
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	if a.config.TLSCertFile != "" {
+		protocols.SetHTTP2(true)
+	} else {
+		protocols.SetUnencryptedHTTP2(true)
+	}
+
+	return &http.Server{
+		Handler:      http.HandlerFunc(a.serveHTTP),
+		ReadTimeout:  a.config.ReadTimeout,
+		WriteTimeout: a.config.WriteTimeout,
+		IdleTimeout:  a.config.IdleTimeout,
+		Protocols:    &protocols,
+		ErrorLog:     log.New(&httpLogWriter{logger: a.logger}, "", 0),
+		ConnState: func(conn net.Conn, state http.ConnState) {
+			a.conns.transition(conn, state.String())
+		},
+	}
+}
+
+func (a *App) serveHTTP2(ln net.Listener) error {
+	var err error
+	if a.config.TLSCertFile != "" {
+		err = a.h2server.ServeTLS(ln, a.config.TLSCertFile, a.config.TLSKeyFile)
+	} else {
+		err = a.h2server.Serve(ln)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (a *App) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var req fasthttp.Request
+	req.Header.SetMethod(r.Method)
+	req.SetRequestURI(r.URL.RequestURI())
+	req.Header.SetHost(r.Host)
+	for k, values := range r.Header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+
+	var fctx fasthttp.RequestCtx
+	remote, _ := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+	conn := requestConn(r, remote)
+
+	limit := a.config.MaxRequestBodySize
+	if limit <= 0 {
+		limit = fasthttp.DefaultMaxRequestBodySize
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, int64(limit)+1))
+	switch {
+	case err != nil:
+		a.initRequestCtx(&fctx, &req, conn)
+		a.handleServerError(&fctx, err)
+	case len(body) > limit:
+		a.initRequestCtx(&fctx, &req, conn)
+		a.handleServerError(&fctx, fasthttp.ErrBodyTooLarge)
+	default:
+		req.SetBodyRaw(body)
+		a.initRequestCtx(&fctx, &req, conn)
+		context.SetEarlyHints(&fctx, func(links []string) error {
+			header := w.Header()
+			for _, link := range links {
+				header.Add("Link", link)
+			}
+			w.WriteHeader(http.StatusEarlyHints)
+			header.Del("Link")
+			return nil
+		})
+		a.handleRequest(&fctx)
+	}
+
+	header := w.Header()
+	for k, v := range fctx.Response.Header.All() {
+		switch string(k) {
+		case "Content-Length", "Connection", "Transfer-Encoding":
+			continue
+		}
+		header.Add(string(k), string(v))
+	}
+	if r.Method == "HEAD" && !fctx.Response.IsBodyStream() {
+		header.Set("Content-Length", strconv.Itoa(len(fctx.Response.Body())))
+	}
+	w.WriteHeader(fctx.Response.StatusCode())
+	if !fctx.Response.IsBodyStream() {
+		if r.Method != "HEAD" {
+			fctx.Response.BodyWriteTo(w)
+		}
+		return
+	}
+	defer fctx.Response.CloseBodyStream()
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil && fctx.Response.ImmediateHeaderFlush {
+		flusher.Flush()
+	}
+	if r.Method != "HEAD" {
+		copyFlushing(w, flusher, fctx.Response.BodyStream())
+	}
+}
+
+type httpConn struct {
+	net.Conn
+	local  net.Addr
+	remote net.Addr
+}
+
+func (c *httpConn) LocalAddr() net.Addr  { return c.local }
+func (c *httpConn) RemoteAddr() net.Addr { return c.remote }
+
+type tlsHTTPConn struct {
+	httpConn
+	state tls.ConnectionState
+}
+
+func (c *tlsHTTPConn) Handshake() error                     { return nil }
+func (c *tlsHTTPConn) ConnectionState() tls.ConnectionState { return c.state }
+
+func requestConn(r *http.Request, remote *net.TCPAddr) net.Conn {
+	conn := httpConn{local: &net.TCPAddr{}, remote: &net.TCPAddr{}}
+	if remote != nil {
+		conn.remote = remote
+	}
+	if local, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		conn.local = local
+	}
+	if r.TLS != nil {
+		return &tlsHTTPConn{httpConn: conn, state: *r.TLS}
+	}
+	return &conn
+}
+
+func (a *App) initRequestCtx(fctx *fasthttp.RequestCtx, req *fasthttp.Request, conn net.Conn) {
+	fctx.Init2(conn, &fasthttpLogger{logger: a.logger}, true)
+	req.CopyTo(&fctx.Request)
+}
+
+func copyFlushing(w io.Writer, flusher http.Flusher, r io.Reader) {
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+type httpLogWriter struct {
+	logger logging.Logger
+}
+
+func (w *httpLogWriter) Write(p []byte) (int, error) {
+	w.logger.Debug(strings.TrimSpace(string(p)))
+	return len(p), nil
+}
@@ -0,0 +1,95 @@
+package fastrest
+
+import (
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+
+	"fastrest/context"
+)
+
+func WrapHTTPHandler(h http.Handler) Handler {
+	return func(c *context.Ctx) error {
+		params := c.Params
+		handler := fasthttpadaptor.NewFastHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range params {
+				r.SetPathValue(k, v)
+			}
+			h.ServeHTTP(w, r)
+		}))
+		handler(c.RequestCtx)
+		return nil
+	}
+}
+
+func WrapHTTPHandlerFunc(fn http.HandlerFunc) Handler {
+	return WrapHTTPHandler(fn)
+}
+
+func WrapHTTPMiddleware(mw func(http.Handler) http.Handler) Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			var r http.Request
+			if err := fasthttpadaptor.ConvertRequest(c.RequestCtx, &r, true); err != nil {
+				return err
+			}
+			for k, v := range c.Params {
+				r.SetPathValue(k, v)
+			}
+
+			w := &responseWriter{ctx: c.RequestCtx, header: make(http.Header)}
+			var err error
+			mw(http.HandlerFunc(func(w2 http.ResponseWriter, r2 *http.Request) {
+				w.flushHeader()
+				for k, vv := range r2.Header {
+					c.Request.Header.Del(k)
+					for _, v := range vv {
+						c.Request.Header.Add(k, v)
+					}
+				}
+				c.SetContext(r2.Context())
+				err = next(c)
+			})).ServeHTTP(w, r.WithContext(c.Context()))
+			return err
+		}
+	}
+}
+
+type responseWriter struct {
+	ctx         *fasthttp.RequestCtx
+	header      http.Header
+	wroteHeader bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) flushHeader() {
+	for k, vv := range w.header {
+		w.ctx.Response.Header.Del(k)
+		for _, v := range vv {
+			w.ctx.Response.Header.Add(k, v)
+		}
+	}
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.flushHeader()
+	w.ctx.SetStatusCode(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.header.Get("Content-Type") == "" {
+			w.header.Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ctx.Write(p)
+}
@@ -0,0 +1,218 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Bundle struct {
+	fallback string
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+func NewBundle(fallback string) *Bundle {
+	return &Bundle{
+		fallback: normalize(fallback),
+		messages: make(map[string]map[string]string),
+	}
+}
+
+func (b *Bundle) AddMessages(lang string, messages map[string]string) {
+	lang = normalize(lang)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, ok := b.messages[lang]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		b.messages[lang] = m
+	}
+	for k, v := range messages {
+		m[k] = v
+	}
+}
+
+func (b *Bundle) LoadFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return b.parse(filepath.Base(file), data)
+}
+
+func (b *Bundle) LoadFS(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if ext := path.Ext(p); ext != ".json" && ext != ".toml" {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		return b.parse(path.Base(p), data)
+	})
+}
+
+func (b *Bundle) parse(name string, data []byte) error {
+	ext := path.Ext(name)
+	lang := strings.TrimSuffix(name, ext)
+
+	messages := make(map[string]string)
+	switch ext {
+	case ".json":
+		var raw map[string]interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("i18n: %s: %w", name, err)
+		}
+		flatten("", raw, messages)
+	case ".toml":
+		if err := parseTOML(data, messages); err != nil {
+			return fmt.Errorf("i18n: %s: %w", name, err)
+		}
+	default:
+		return fmt.Errorf("i18n: %s: unsupported format", name)
+	}
+	b.AddMessages(lang, messages)
+	return nil
+}
+
+func flatten(prefix string, raw map[string]interface{}, out map[string]string) {
+	for k, v := range raw {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case map[string]interface{}:
+			flatten(key, val, out)
+		case string:
+			out[key] = val
+		default:
+			out[key] = fmt.Sprint(val)
+		}
+	}
+}
+
+func (b *Bundle) Languages() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	langs := make([]string, 0, len(b.messages))
+	for lang := range b.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+func (b *Bundle) Localizer(langs ...string) *Localizer {
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(lang string) {
+		if lang != "" && !seen[lang] {
+			seen[lang] = true
+			chain = append(chain, lang)
+		}
+	}
+	for _, lang := range langs {
+		lang = normalize(lang)
+		add(lang)
+		if base, _, ok := strings.Cut(lang, "-"); ok {
+			add(base)
+		}
+	}
+	add(b.fallback)
+
+	l := &Localizer{bundle: b, chain: chain, lang: b.fallback}
+	b.mu.RLock()
+	for _, lang := range chain {
+		if _, ok := b.messages[lang]; ok {
+			l.lang = lang
+			break
+		}
+	}
+	b.mu.RUnlock()
+	return l
+}
+
+func (b *Bundle) lookup(chain []string, key string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, lang := range chain {
+		if msg, ok := b.messages[lang][key]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+type Localizer struct {
+	bundle *Bundle
+	chain  []string
+	lang   string
+}
+
+func (l *Localizer) Lang() string {
+	return l.lang
+}
+
+func (l *Localizer) T(key string, args ...interface{}) string {
+	msg, ok := l.bundle.lookup(l.chain, key)
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+func ParseAcceptLanguage(header string) []string {
+	type tag struct {
+		lang string
+		q    float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang = strings.TrimSpace(lang)
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			tags = append(tags, tag{lang: lang, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	langs := make([]string, len(tags))
+	for i, t := range tags {
+		langs[i] = t.lang
+	}
+	return langs
+}
+
+func normalize(lang string) string {
+	lang = strings.ReplaceAll(strings.TrimSpace(lang), "_", "-")
+	base, region, ok := strings.Cut(lang, "-")
+	if !ok {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(base) + "-" + strings.ToUpper(region)
+}
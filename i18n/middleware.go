@@ -0,0 +1,23 @@
+package i18n
+
+import (
+	"fastrest/context"
+)
+
+func (b *Bundle) Middleware() context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			var langs []string
+			if lang := c.Query("lang"); lang != "" {
+				langs = append(langs, lang)
+			}
+			langs = append(langs, ParseAcceptLanguage(c.Get("Accept-Language"))...)
+
+			l := b.Localizer(langs...)
+			c.SetTranslator(l)
+			c.Set("Content-Language", l.Lang())
+			c.Response.Header.Add("Vary", "Accept-Language")
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func parseTOML(data []byte, out map[string]string) error {
+	section := ""
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value", n+1)
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"`)
+		value = strings.TrimSpace(value)
+
+		var str string
+		switch {
+		case strings.HasPrefix(value, `"`):
+			end := closingQuote(value)
+			if end < 0 {
+				return fmt.Errorf("line %d: unterminated string", n+1)
+			}
+			unquoted, err := strconv.Unquote(value[:end+1])
+			if err != nil {
+				return fmt.Errorf("line %d: %w", n+1, err)
+			}
+			str = unquoted
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return fmt.Errorf("line %d: unterminated string", n+1)
+			}
+			str = value[1 : end+1]
+		default:
+			str, _, _ = strings.Cut(value, "#")
+			str = strings.TrimSpace(str)
+		}
+
+		if section != "" {
+			key = section + "." + key
+		}
+		out[key] = str
+	}
+	return nil
+}
+
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,104 @@
+package fastrest
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type LifecycleEvent string
+
+const (
+	EventConfigLoaded      LifecycleEvent = "config_loaded"
+	EventRoutesCompiled    LifecycleEvent = "routes_compiled"
+	EventListenerBound     LifecycleEvent = "listener_bound"
+	EventFirstRequest      LifecycleEvent = "first_request_served"
+	EventShutdownInitiated LifecycleEvent = "shutdown_initiated"
+	EventDrained           LifecycleEvent = "drained"
+)
+
+type Lifecycle struct {
+	Event     LifecycleEvent         `json:"event"`
+	Time      time.Time              `json:"time"`
+	SinceBoot time.Duration          `json:"since_boot"`
+	SinceLast time.Duration          `json:"since_last"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+type LifecycleHook func(Lifecycle)
+
+type lifecycle struct {
+	mu           sync.Mutex
+	boot         time.Time
+	last         time.Time
+	events       []Lifecycle
+	hooks        []LifecycleHook
+	firstRequest int32
+}
+
+func newLifecycle(boot time.Time) *lifecycle {
+	return &lifecycle{boot: boot, last: boot}
+}
+
+func (a *App) OnLifecycle(hook LifecycleHook) {
+	a.lifecycle.mu.Lock()
+	a.lifecycle.hooks = append(a.lifecycle.hooks, hook)
+	past := append([]Lifecycle{}, a.lifecycle.events...)
+	a.lifecycle.mu.Unlock()
+
+	for _, event := range past {
+		hook(event)
+	}
+}
+
+func (a *App) LifecycleEvents() []Lifecycle {
+	a.lifecycle.mu.Lock()
+	defer a.lifecycle.mu.Unlock()
+	return append([]Lifecycle{}, a.lifecycle.events...)
+}
+
+func (a *App) emit(event LifecycleEvent, fields map[string]interface{}) {
+	l := a.lifecycle
+	now := a.clock.Now()
+
+	l.mu.Lock()
+	e := Lifecycle{
+		Event:     event,
+		Time:      now,
+		SinceBoot: now.Sub(l.boot),
+		SinceLast: now.Sub(l.last),
+		Fields:    fields,
+	}
+	l.last = now
+	l.events = append(l.events, e)
+	hooks := append([]LifecycleHook{}, l.hooks...)
+	l.mu.Unlock()
+
+	args := []interface{}{
+		"event", string(event),
+		"since_boot_ms", float64(e.SinceBoot) / float64(time.Millisecond),
+		"since_last_ms", float64(e.SinceLast) / float64(time.Millisecond),
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, k, fields[k])
+	}
+	a.logger.Info("lifecycle", args...)
+
+	for _, hook := range hooks {
+		hook(e)
+	}
+}
+
+func (a *App) markFirstRequest(method, path string) {
+	if atomic.LoadInt32(&a.lifecycle.firstRequest) == 1 ||
+		!atomic.CompareAndSwapInt32(&a.lifecycle.firstRequest, 0, 1) {
+		return
+	}
+	a.emit(EventFirstRequest, map[string]interface{}{"method": method, "path": path})
+}
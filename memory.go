@@ -0,0 +1,162 @@
+package fastrest
+
+import (
+	stdctx "context"
+	"errors"
+	"math"
+	"runtime/debug"
+	rtmetrics "runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+	"fastrest/pkg/logging"
+)
+
+var errMemoryPressure = errors.New("memory usage above high watermark")
+
+var memorySampleNames = []string{
+	"/memory/classes/total:bytes",
+	"/memory/classes/heap/released:bytes",
+}
+
+type memoryWatchdog struct {
+	limit     uint64
+	high      float64
+	low       float64
+	interval  time.Duration
+	logger    logging.Logger
+	pressured int32
+	mu        sync.Mutex
+	hooks     []func()
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+func newMemoryWatchdog(limit uint64, high float64, interval time.Duration, logger logging.Logger) *memoryWatchdog {
+	if limit == 0 {
+		if l := debug.SetMemoryLimit(-1); l > 0 && l != math.MaxInt64 {
+			limit = uint64(l)
+		}
+	}
+	if limit == 0 {
+		return nil
+	}
+	if high <= 0 || high >= 1 {
+		high = 0.9
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &memoryWatchdog{
+		limit:    limit,
+		high:     high,
+		low:      high - 0.1,
+		interval: interval,
+		logger:   logger,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (w *memoryWatchdog) start() {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.check()
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (w *memoryWatchdog) close() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+func (w *memoryWatchdog) check() {
+	used := memoryInUse()
+	ratio := float64(used) / float64(w.limit)
+
+	if ratio >= w.high && atomic.CompareAndSwapInt32(&w.pressured, 0, 1) {
+		w.logger.Warn("memory pressure detected, shedding load",
+			"used_bytes", used, "limit_bytes", w.limit, "ratio", ratio)
+		w.mu.Lock()
+		hooks := append([]func(){}, w.hooks...)
+		w.mu.Unlock()
+		for _, hook := range hooks {
+			hook()
+		}
+		debug.FreeOSMemory()
+		return
+	}
+
+	if ratio < w.low && atomic.CompareAndSwapInt32(&w.pressured, 1, 0) {
+		w.logger.Info("memory pressure relieved", "used_bytes", used, "limit_bytes", w.limit, "ratio", ratio)
+	}
+}
+
+func (w *memoryWatchdog) underPressure() bool {
+	return w != nil && atomic.LoadInt32(&w.pressured) == 1
+}
+
+func (w *memoryWatchdog) onPressure(hook func()) {
+	w.mu.Lock()
+	w.hooks = append(w.hooks, hook)
+	w.mu.Unlock()
+}
+
+func memoryInUse() uint64 {
+	samples := make([]rtmetrics.Sample, len(memorySampleNames))
+	for i, name := range memorySampleNames {
+		samples[i].Name = name
+	}
+	rtmetrics.Read(samples)
+
+	var total, released uint64
+	if samples[0].Value.Kind() == rtmetrics.KindUint64 {
+		total = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == rtmetrics.KindUint64 {
+		released = samples[1].Value.Uint64()
+	}
+	if released > total {
+		return 0
+	}
+	return total - released
+}
+
+func (a *App) memoryCheck(ctx stdctx.Context) error {
+	if a.memory.underPressure() {
+		return errMemoryPressure
+	}
+	return nil
+}
+
+func (a *App) OnMemoryPressure(hook func()) {
+	if a.memory != nil {
+		a.memory.onPressure(hook)
+	}
+}
+
+func (a *App) UnderMemoryPressure() bool {
+	return a.memory.underPressure()
+}
+
+func (a *App) shedLoad(c *context.Ctx, path string) bool {
+	if !a.memory.underPressure() {
+		return false
+	}
+	if a.config.HealthCheck && (path == a.config.HealthPath || len(path) > len(a.config.HealthPath) &&
+		path[:len(a.config.HealthPath)+1] == a.config.HealthPath+"/") {
+		return false
+	}
+	c.Set("Retry-After", "5")
+	c.SendError(constant.StatusServiceUnavailable, "server under memory pressure")
+	return true
+}
@@ -0,0 +1,275 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var DefaultCustomBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type metricKind string
+
+const (
+	kindCounter   metricKind = "counter"
+	kindGauge     metricKind = "gauge"
+	kindHistogram metricKind = "histogram"
+)
+
+type customKey struct {
+	name   string
+	labels string
+}
+
+type Counter struct {
+	bits uint64
+}
+
+type Gauge struct {
+	bits uint64
+}
+
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []int64
+	sum     float64
+	count   int64
+}
+
+type HistogramJSON struct {
+	Count   int64            `json:"count"`
+	Sum     float64          `json:"sum"`
+	Buckets map[string]int64 `json:"buckets"`
+}
+
+func (m *Metrics) Counter(name string, labels ...string) *Counter {
+	if m == nil {
+		return &Counter{}
+	}
+	return m.loadCustom(kindCounter, name, labels, func() interface{} { return &Counter{} }).(*Counter)
+}
+
+func (m *Metrics) Gauge(name string, labels ...string) *Gauge {
+	if m == nil {
+		return &Gauge{}
+	}
+	return m.loadCustom(kindGauge, name, labels, func() interface{} { return &Gauge{} }).(*Gauge)
+}
+
+func (m *Metrics) Histogram(name string, labels ...string) *Histogram {
+	return m.HistogramWithBuckets(name, nil, labels...)
+}
+
+func (m *Metrics) HistogramWithBuckets(name string, buckets []float64, labels ...string) *Histogram {
+	if m == nil {
+		return newHistogram(buckets)
+	}
+	return m.loadCustom(kindHistogram, name, labels, func() interface{} { return newHistogram(buckets) }).(*Histogram)
+}
+
+func (m *Metrics) loadCustom(kind metricKind, name string, labels []string, create func() interface{}) interface{} {
+	if existing, ok := m.customKinds.LoadOrStore(name, kind); ok && existing.(metricKind) != kind {
+		panic(fmt.Sprintf("metrics: %q already registered as %s, not %s", name, existing, kind))
+	}
+
+	key := customKey{name: name, labels: customLabels(labels)}
+	if val, ok := m.custom.Load(key); ok {
+		return val
+	}
+	val, _ := m.custom.LoadOrStore(key, create())
+	return val
+}
+
+func customLabels(labels []string) string {
+	if len(labels) < 2 {
+		return ""
+	}
+	type pair struct{ k, v string }
+	pairs := make([]pair, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, pair{labels[i], labels[i+1]})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].k < pairs[j].k })
+
+	flat := make([]string, 0, len(pairs)*2)
+	for _, p := range pairs {
+		flat = append(flat, p.k, p.v)
+	}
+	return formatLabels(flat...)
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	addFloat(&c.bits, delta)
+}
+
+func (c *Counter) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&c.bits))
+}
+
+func (g *Gauge) Set(value float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(value))
+}
+
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+func (g *Gauge) Add(delta float64) {
+	addFloat(&g.bits, delta)
+}
+
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultCustomBuckets
+	}
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+	return &Histogram{
+		buckets: sorted,
+		counts:  make([]int64, len(sorted)),
+	}
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sum += value
+	h.count++
+	for i, le := range h.buckets {
+		if value <= le {
+			h.counts[i]++
+		}
+	}
+}
+
+func (h *Histogram) reset() {
+	h.mu.Lock()
+	h.counts = make([]int64, len(h.buckets))
+	h.sum, h.count = 0, 0
+	h.mu.Unlock()
+}
+
+func (h *Histogram) snapshot() HistogramJSON {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	result := HistogramJSON{
+		Count:   h.count,
+		Sum:     h.sum,
+		Buckets: make(map[string]int64, len(h.buckets)+1),
+	}
+	for i, le := range h.buckets {
+		result.Buckets[formatFloat(le)] = h.counts[i]
+	}
+	result.Buckets["+Inf"] = h.count
+	return result
+}
+
+func addFloat(bits *uint64, delta float64) {
+	for {
+		old := atomic.LoadUint64(bits)
+		next := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(bits, old, next) {
+			return
+		}
+	}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func (m *Metrics) sortedCustomKeys() []customKey {
+	var keys []customKey
+	m.custom.Range(func(key, value interface{}) bool {
+		keys = append(keys, key.(customKey))
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].labels < keys[j].labels
+	})
+	return keys
+}
+
+func (m *Metrics) writeCustomPrometheus(sb *strings.Builder) {
+	lastName := ""
+	for _, key := range m.sortedCustomKeys() {
+		val, _ := m.custom.Load(key)
+		if key.name != lastName {
+			kind, _ := m.customKinds.Load(key.name)
+			sb.WriteString(fmt.Sprintf("\n# TYPE %s %s\n", key.name, kind))
+			lastName = key.name
+		}
+
+		switch metric := val.(type) {
+		case *Counter:
+			sb.WriteString(fmt.Sprintf("%s%s %s\n", key.name, key.labels, formatFloat(metric.Value())))
+		case *Gauge:
+			sb.WriteString(fmt.Sprintf("%s%s %s\n", key.name, key.labels, formatFloat(metric.Value())))
+		case *Histogram:
+			snap := metric.snapshot()
+			for _, le := range metric.buckets {
+				sb.WriteString(fmt.Sprintf("%s_bucket%s %d\n", key.name,
+					withLabel(key.labels, "le", formatFloat(le)), snap.Buckets[formatFloat(le)]))
+			}
+			sb.WriteString(fmt.Sprintf("%s_bucket%s %d\n", key.name, withLabel(key.labels, "le", "+Inf"), snap.Count))
+			sb.WriteString(fmt.Sprintf("%s_sum%s %s\n", key.name, key.labels, formatFloat(snap.Sum)))
+			sb.WriteString(fmt.Sprintf("%s_count%s %d\n", key.name, key.labels, snap.Count))
+		}
+	}
+}
+
+func (m *Metrics) writeCustomJSON(result *MetricsJSON) {
+	m.custom.Range(func(k, value interface{}) bool {
+		key := k.(customKey)
+		id := key.name + key.labels
+		switch metric := value.(type) {
+		case *Counter:
+			if result.Counters == nil {
+				result.Counters = make(map[string]float64)
+			}
+			result.Counters[id] = metric.Value()
+		case *Gauge:
+			if result.Gauges == nil {
+				result.Gauges = make(map[string]float64)
+			}
+			result.Gauges[id] = metric.Value()
+		case *Histogram:
+			if result.Histograms == nil {
+				result.Histograms = make(map[string]HistogramJSON)
+			}
+			result.Histograms[id] = metric.snapshot()
+		}
+		return true
+	})
+}
+
+func withLabel(labels, name, value string) string {
+	extra := name + `="` + EscapeLabelValue(value) + `"`
+	if labels == "" {
+		return "{" + extra + "}"
+	}
+	return labels[:len(labels)-1] + "," + extra + "}"
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type DurationKey struct {
+	Method string
+	Path   string
+	Class  string
+}
+
+func (k DurationKey) labels(extra ...string) string {
+	return formatLabels(append([]string{"method", k.Method, "path", k.Path, "status", k.Class}, extra...)...)
+}
+
+func (k DurationKey) less(o DurationKey) bool {
+	if k.Path != o.Path {
+		return k.Path < o.Path
+	}
+	if k.Method != o.Method {
+		return k.Method < o.Method
+	}
+	return k.Class < o.Class
+}
+
+type exemplar struct {
+	traceID string
+	value   float64
+	at      time.Time
+}
+
+type durationHistogram struct {
+	mu        sync.Mutex
+	counts    []int64
+	sum       float64
+	count     int64
+	exemplars []exemplar
+}
+
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+func (m *Metrics) observeDuration(method, path string, status int, duration time.Duration, traceID string) {
+	key := DurationKey{Method: method, Path: path, Class: StatusClass(status)}
+	val, ok := m.requestDuration.Load(key)
+	if !ok {
+		val, _ = m.requestDuration.LoadOrStore(key, &durationHistogram{
+			counts:    make([]int64, len(m.buckets)+1),
+			exemplars: make([]exemplar, len(m.buckets)+1),
+		})
+	}
+	h := val.(*durationHistogram)
+
+	seconds := duration.Seconds()
+	i := sort.SearchFloat64s(m.buckets, seconds*1000)
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += seconds
+	h.count++
+	if traceID != "" {
+		h.exemplars[i] = exemplar{traceID: traceID, value: seconds, at: m.clock.Now()}
+	}
+	h.mu.Unlock()
+}
+
+func (m *Metrics) writeDurationPrometheus(sb *strings.Builder, openMetrics bool) {
+	var keys []DurationKey
+	m.requestDuration.Range(func(key, _ interface{}) bool {
+		keys = append(keys, key.(DurationKey))
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
+	sb.WriteString("\n# HELP http_request_duration_seconds HTTP request latency in seconds\n")
+	sb.WriteString("# TYPE http_request_duration_seconds histogram\n")
+
+	for _, key := range keys {
+		val, _ := m.requestDuration.Load(key)
+		h := val.(*durationHistogram)
+		h.mu.Lock()
+		counts := append([]int64{}, h.counts...)
+		exemplars := append([]exemplar{}, h.exemplars...)
+		sum, count := h.sum, h.count
+		h.mu.Unlock()
+
+		var cumulative int64
+		for i := range counts {
+			cumulative += counts[i]
+			le := "+Inf"
+			if i < len(m.buckets) {
+				le = strconv.FormatFloat(m.buckets[i]/1000, 'g', -1, 64)
+			}
+			sb.WriteString(fmt.Sprintf("http_request_duration_seconds_bucket%s %d", key.labels("le", le), cumulative))
+			if ex := exemplars[i]; openMetrics && ex.traceID != "" {
+				sb.WriteString(fmt.Sprintf(" # {trace_id=\"%s\"} %s %.3f", EscapeLabelValue(ex.traceID),
+					strconv.FormatFloat(ex.value, 'g', -1, 64), float64(ex.at.UnixNano())/1e9))
+			}
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(fmt.Sprintf("http_request_duration_seconds_sum%s %s\n", key.labels(), strconv.FormatFloat(sum, 'g', -1, 64)))
+		sb.WriteString(fmt.Sprintf("http_request_duration_seconds_count%s %d\n", key.labels(), count))
+	}
+}
+
+func toOpenMetrics(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	counters := make(map[string]string)
+	for _, line := range lines {
+		if rest, ok := strings.CutPrefix(line, "# TYPE "); ok {
+			name, kind, _ := strings.Cut(rest, " ")
+			if kind == "counter" {
+				counters[name] = strings.TrimSuffix(name, "_total")
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for _, line := range lines {
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "# HELP ") || strings.HasPrefix(line, "# TYPE "):
+			prefix, rest := line[:7], line[7:]
+			name, tail, _ := strings.Cut(rest, " ")
+			if family, ok := counters[name]; ok {
+				name = family
+			}
+			sb.WriteString(prefix + name + " " + tail)
+		default:
+			name := line
+			if i := strings.IndexAny(line, "{ "); i >= 0 {
+				name = line[:i]
+			}
+			if family, ok := counters[name]; ok && family == name {
+				line = family + "_total" + line[len(name):]
+			}
+			sb.WriteString(line)
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("# EOF\n")
+	return sb.String()
+}
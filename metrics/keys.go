@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"strconv"
+	"strings"
+)
+
+type RequestKey struct {
+	Method string
+	Path   string
+	Status int
+}
+
+type RouteKey struct {
+	Method string
+	Path   string
+}
+
+type ErrorKey struct {
+	Method string
+	Path   string
+	Type   string
+}
+
+func (k RequestKey) String() string {
+	return k.Method + "_" + k.Path + "_" + strconv.Itoa(k.Status)
+}
+
+func (k RequestKey) labels() string {
+	return formatLabels("method", k.Method, "path", k.Path, "status", strconv.Itoa(k.Status))
+}
+
+func (k RequestKey) less(o RequestKey) bool {
+	if k.Method != o.Method {
+		return k.Method < o.Method
+	}
+	if k.Path != o.Path {
+		return k.Path < o.Path
+	}
+	return k.Status < o.Status
+}
+
+func (k RouteKey) String() string {
+	return k.Method + "_" + k.Path
+}
+
+func (k RouteKey) labels(extra ...string) string {
+	return formatLabels(append([]string{"method", k.Method, "path", k.Path}, extra...)...)
+}
+
+func (k RouteKey) less(o RouteKey) bool {
+	if k.Method != o.Method {
+		return k.Method < o.Method
+	}
+	return k.Path < o.Path
+}
+
+func (k ErrorKey) String() string {
+	return k.Method + "_" + k.Path + "_" + k.Type
+}
+
+func (k ErrorKey) labels() string {
+	return formatLabels("method", k.Method, "path", k.Path, "type", k.Type)
+}
+
+func (k ErrorKey) less(o ErrorKey) bool {
+	if k.Method != o.Method {
+		return k.Method < o.Method
+	}
+	if k.Path != o.Path {
+		return k.Path < o.Path
+	}
+	return k.Type < o.Type
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func EscapeLabelValue(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatLabels(pairs ...string) string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(pairs[i])
+		sb.WriteString(`="`)
+		sb.WriteString(EscapeLabelValue(pairs[i+1]))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
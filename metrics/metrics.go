@@ -3,66 +3,152 @@ package metrics
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"fastrest/pkg/clock"
 )
 
 type Metrics struct {
-	requestTotal   sync.Map
-	requestLatency sync.Map
-	errorTotal     sync.Map
-	logCount       sync.Map
-	activeConns    int64
-	startTime      time.Time
+	requestTotal    sync.Map
+	requestLatency  sync.Map
+	requestDuration sync.Map
+	errorTotal      sync.Map
+	logCount        sync.Map
+	allocations     sync.Map
+	custom          sync.Map
+	customKinds     sync.Map
+	requestWindow   rateWindow
+	errorWindow     rateWindow
+	activeConns     int64
+	startTime       time.Time
+	buckets         []float64
+	clock           clock.Clock
 }
 
 type LatencyBucket struct {
-	sum   float64
-	count int64
+	mu     sync.Mutex
+	sum    float64
+	count  int64
+	counts []int64
+}
+
+var DefaultBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+type AllocStats struct {
+	mu       sync.Mutex
+	count    int64
+	bytes    uint64
+	objects  uint64
+	maxBytes uint64
+}
+
+type AllocJSON struct {
+	Samples    int64   `json:"samples"`
+	AvgBytes   float64 `json:"avg_bytes"`
+	AvgObjects float64 `json:"avg_objects"`
+	MaxBytes   uint64  `json:"max_bytes"`
 }
 
 type MetricsJSON struct {
-	Requests     map[string]int64   `json:"requests"`
-	Errors       map[string]int64   `json:"errors"`
-	Latencies    map[string]float64 `json:"latencies_ms"`
-	Logs         map[string]int64   `json:"logs"`
-	ActiveConns  int64              `json:"active_connections"`
-	UptimeSecond float64            `json:"uptime_seconds"`
+	Requests     map[string]int64         `json:"requests"`
+	Errors       map[string]int64         `json:"errors"`
+	Latencies    map[string]float64       `json:"latencies_ms"`
+	Logs         map[string]int64         `json:"logs"`
+	Allocations  map[string]AllocJSON     `json:"allocations,omitempty"`
+	Counters     map[string]float64       `json:"counters,omitempty"`
+	Gauges       map[string]float64       `json:"gauges,omitempty"`
+	Histograms   map[string]HistogramJSON `json:"histograms,omitempty"`
+	ActiveConns  int64                    `json:"active_connections"`
+	UptimeSecond float64                  `json:"uptime_seconds"`
+	Rates        *RatesJSON               `json:"rates"`
+}
+
+type RatesJSON struct {
+	Requests1m float64 `json:"requests_per_second_1m"`
+	Requests5m float64 `json:"requests_per_second_5m"`
+	Errors1m   float64 `json:"errors_per_second_1m"`
+	Errors5m   float64 `json:"errors_per_second_5m"`
 }
 
 func New() *Metrics {
+	return NewWithBuckets(nil)
+}
+
+func NewWithBuckets(buckets []float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
 	return &Metrics{
 		startTime: time.Now(),
+		buckets:   sorted,
+		clock:     clock.System,
 	}
 }
 
+func (m *Metrics) SetClock(c clock.Clock) {
+	m.clock = c
+	m.startTime = c.Now()
+}
+
+func (m *Metrics) Buckets() []float64 {
+	return append([]float64{}, m.buckets...)
+}
+
 func (m *Metrics) IncRequestTotal(method, path string, status int) {
-	key := fmt.Sprintf("%s_%s_%d", method, path, status)
+	key := RequestKey{Method: method, Path: path, Status: status}
 	val, _ := m.requestTotal.LoadOrStore(key, new(int64))
 	atomic.AddInt64(val.(*int64), 1)
+	m.requestWindow.add(m.clock.Now())
 }
 
-func (m *Metrics) ObserveLatency(method, path string, duration time.Duration) {
-	key := fmt.Sprintf("%s_%s", method, path)
-	val, _ := m.requestLatency.LoadOrStore(key, &sync.Mutex{})
-	mu := val.(*sync.Mutex)
+func (m *Metrics) ObserveLatency(method, path string, status int, duration time.Duration, traceID string) {
+	m.observeDuration(method, path, status, duration, traceID)
 
-	bucketKey := key + "_bucket"
-	bucketVal, _ := m.requestLatency.LoadOrStore(bucketKey, &LatencyBucket{})
-	bucket := bucketVal.(*LatencyBucket)
+	key := RouteKey{Method: method, Path: path}
+	val, ok := m.requestLatency.Load(key)
+	if !ok {
+		val, _ = m.requestLatency.LoadOrStore(key, &LatencyBucket{counts: make([]int64, len(m.buckets))})
+	}
+	bucket := val.(*LatencyBucket)
 
-	mu.Lock()
-	bucket.sum += float64(duration.Milliseconds())
+	ms := float64(duration) / float64(time.Millisecond)
+	bucket.mu.Lock()
+	bucket.sum += ms
 	bucket.count++
-	mu.Unlock()
+	for i, le := range m.buckets {
+		if ms <= le {
+			bucket.counts[i]++
+		}
+	}
+	bucket.mu.Unlock()
 }
 
 func (m *Metrics) IncError(method, path, errorType string) {
-	key := fmt.Sprintf("%s_%s_%s", method, path, errorType)
+	key := ErrorKey{Method: method, Path: path, Type: errorType}
 	val, _ := m.errorTotal.LoadOrStore(key, new(int64))
 	atomic.AddInt64(val.(*int64), 1)
+	m.errorWindow.add(m.clock.Now())
+}
+
+func (m *Metrics) ObserveAllocs(method, path string, bytes, objects uint64) {
+	key := RouteKey{Method: method, Path: path}
+	val, _ := m.allocations.LoadOrStore(key, &AllocStats{})
+	stats := val.(*AllocStats)
+
+	stats.mu.Lock()
+	stats.count++
+	stats.bytes += bytes
+	stats.objects += objects
+	if bytes > stats.maxBytes {
+		stats.maxBytes = bytes
+	}
+	stats.mu.Unlock()
 }
 
 func (m *Metrics) IncLogCount(level string) {
@@ -79,79 +165,111 @@ func (m *Metrics) DecActiveConns() {
 }
 
 func (m *Metrics) ToPrometheus() string {
+	return m.writePrometheus(false)
+}
+
+func (m *Metrics) ToOpenMetrics() string {
+	return toOpenMetrics(m.writePrometheus(true))
+}
+
+func (m *Metrics) writePrometheus(openMetrics bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("# HELP http_requests_total Total number of HTTP requests\n")
 	sb.WriteString("# TYPE http_requests_total counter\n")
 
-	var requestKeys []string
+	var requestKeys []RequestKey
 	m.requestTotal.Range(func(key, value interface{}) bool {
-		requestKeys = append(requestKeys, key.(string))
+		requestKeys = append(requestKeys, key.(RequestKey))
 		return true
 	})
-	sort.Strings(requestKeys)
+	sort.Slice(requestKeys, func(i, j int) bool { return requestKeys[i].less(requestKeys[j]) })
 
 	for _, key := range requestKeys {
 		val, _ := m.requestTotal.Load(key)
-		parts := strings.SplitN(key, "_", 3)
-		if len(parts) == 3 {
-			sb.WriteString(fmt.Sprintf("http_requests_total{method=\"%s\",path=\"%s\",status=\"%s\"} %d\n",
-				parts[0], parts[1], parts[2], atomic.LoadInt64(val.(*int64))))
-		}
+		sb.WriteString(fmt.Sprintf("http_requests_total%s %d\n", key.labels(), atomic.LoadInt64(val.(*int64))))
 	}
 
 	sb.WriteString("\n# HELP http_request_duration_ms HTTP request latency in milliseconds\n")
-	sb.WriteString("# TYPE http_request_duration_ms gauge\n")
-
-	var latencyKeys []string
-	m.requestLatency.Range(func(key, value interface{}) bool {
-		if strings.HasSuffix(key.(string), "_bucket") {
-			latencyKeys = append(latencyKeys, key.(string))
-		}
-		return true
-	})
-	sort.Strings(latencyKeys)
+	sb.WriteString("# TYPE http_request_duration_ms histogram\n")
 
+	latencyKeys := sortedRouteKeys(&m.requestLatency)
 	for _, key := range latencyKeys {
 		val, _ := m.requestLatency.Load(key)
 		bucket := val.(*LatencyBucket)
-		if bucket.count > 0 {
-			baseKey := strings.TrimSuffix(key, "_bucket")
-			parts := strings.SplitN(baseKey, "_", 2)
-			if len(parts) == 2 {
-				avg := bucket.sum / float64(bucket.count)
-				sb.WriteString(fmt.Sprintf("http_request_duration_ms{method=\"%s\",path=\"%s\"} %.2f\n",
-					parts[0], parts[1], avg))
-			}
+		bucket.mu.Lock()
+		counts := append([]int64{}, bucket.counts...)
+		sum, count := bucket.sum, bucket.count
+		bucket.mu.Unlock()
+
+		for i, le := range m.buckets {
+			sb.WriteString(fmt.Sprintf("http_request_duration_ms_bucket%s %d\n",
+				key.labels("le", strconv.FormatFloat(le, 'g', -1, 64)), counts[i]))
 		}
+		sb.WriteString(fmt.Sprintf("http_request_duration_ms_bucket%s %d\n", key.labels("le", "+Inf"), count))
+		sb.WriteString(fmt.Sprintf("http_request_duration_ms_sum%s %.3f\n", key.labels(), sum))
+		sb.WriteString(fmt.Sprintf("http_request_duration_ms_count%s %d\n", key.labels(), count))
 	}
 
+	m.writeDurationPrometheus(&sb, openMetrics)
+
 	sb.WriteString("\n# HELP http_errors_total Total number of HTTP errors\n")
 	sb.WriteString("# TYPE http_errors_total counter\n")
 
-	var errorKeys []string
+	var errorKeys []ErrorKey
 	m.errorTotal.Range(func(key, value interface{}) bool {
-		errorKeys = append(errorKeys, key.(string))
+		errorKeys = append(errorKeys, key.(ErrorKey))
 		return true
 	})
-	sort.Strings(errorKeys)
+	sort.Slice(errorKeys, func(i, j int) bool { return errorKeys[i].less(errorKeys[j]) })
 
 	for _, key := range errorKeys {
 		val, _ := m.errorTotal.Load(key)
-		parts := strings.SplitN(key, "_", 3)
-		if len(parts) == 3 {
-			sb.WriteString(fmt.Sprintf("http_errors_total{method=\"%s\",path=\"%s\",type=\"%s\"} %d\n",
-				parts[0], parts[1], parts[2], atomic.LoadInt64(val.(*int64))))
+		sb.WriteString(fmt.Sprintf("http_errors_total%s %d\n", key.labels(), atomic.LoadInt64(val.(*int64))))
+	}
+
+	allocKeys := sortedRouteKeys(&m.allocations)
+	if len(allocKeys) > 0 {
+		allocs := make([]AllocJSON, len(allocKeys))
+		for i, key := range allocKeys {
+			val, _ := m.allocations.Load(key)
+			allocs[i] = val.(*AllocStats).snapshot()
+		}
+
+		sb.WriteString("\n# HELP http_request_alloc_bytes Average process-wide heap bytes allocated while a sampled request ran\n")
+		sb.WriteString("# TYPE http_request_alloc_bytes gauge\n")
+		for i, key := range allocKeys {
+			if allocs[i].Samples > 0 {
+				sb.WriteString(fmt.Sprintf("http_request_alloc_bytes%s %.2f\n", key.labels(), allocs[i].AvgBytes))
+			}
+		}
+
+		sb.WriteString("\n# HELP http_request_alloc_objects Average process-wide heap objects allocated while a sampled request ran\n")
+		sb.WriteString("# TYPE http_request_alloc_objects gauge\n")
+		for i, key := range allocKeys {
+			if allocs[i].Samples > 0 {
+				sb.WriteString(fmt.Sprintf("http_request_alloc_objects%s %.2f\n", key.labels(), allocs[i].AvgObjects))
+			}
+		}
+
+		sb.WriteString("\n# HELP http_request_alloc_bytes_max Peak process-wide heap bytes allocated while a sampled request ran\n")
+		sb.WriteString("# TYPE http_request_alloc_bytes_max gauge\n")
+		for i, key := range allocKeys {
+			if allocs[i].Samples > 0 {
+				sb.WriteString(fmt.Sprintf("http_request_alloc_bytes_max%s %d\n", key.labels(), allocs[i].MaxBytes))
+			}
 		}
 	}
 
-	sb.WriteString(fmt.Sprintf("\n# HELP active_connections Current active connections\n"))
-	sb.WriteString(fmt.Sprintf("# TYPE active_connections gauge\n"))
+	sb.WriteString("\n# HELP active_connections Current active connections\n")
+	sb.WriteString("# TYPE active_connections gauge\n")
 	sb.WriteString(fmt.Sprintf("active_connections %d\n", atomic.LoadInt64(&m.activeConns)))
 
-	sb.WriteString(fmt.Sprintf("\n# HELP uptime_seconds Server uptime in seconds\n"))
-	sb.WriteString(fmt.Sprintf("# TYPE uptime_seconds gauge\n"))
-	sb.WriteString(fmt.Sprintf("uptime_seconds %.2f\n", time.Since(m.startTime).Seconds()))
+	sb.WriteString("\n# HELP uptime_seconds Server uptime in seconds\n")
+	sb.WriteString("# TYPE uptime_seconds gauge\n")
+	sb.WriteString(fmt.Sprintf("uptime_seconds %.2f\n", m.clock.Since(m.startTime).Seconds()))
+
+	m.writeCustomPrometheus(&sb)
 
 	return sb.String()
 }
@@ -163,27 +281,34 @@ func (m *Metrics) ToJSON() *MetricsJSON {
 		Latencies:    make(map[string]float64),
 		Logs:         make(map[string]int64),
 		ActiveConns:  atomic.LoadInt64(&m.activeConns),
-		UptimeSecond: time.Since(m.startTime).Seconds(),
+		UptimeSecond: m.clock.Since(m.startTime).Seconds(),
+	}
+
+	now := m.clock.Now()
+	result.Rates = &RatesJSON{
+		Requests1m: m.requestWindow.rate(now, time.Minute),
+		Requests5m: m.requestWindow.rate(now, 5*time.Minute),
+		Errors1m:   m.errorWindow.rate(now, time.Minute),
+		Errors5m:   m.errorWindow.rate(now, 5*time.Minute),
 	}
 
 	m.requestTotal.Range(func(key, value interface{}) bool {
-		result.Requests[key.(string)] = atomic.LoadInt64(value.(*int64))
+		result.Requests[key.(RequestKey).String()] = atomic.LoadInt64(value.(*int64))
 		return true
 	})
 
 	m.errorTotal.Range(func(key, value interface{}) bool {
-		result.Errors[key.(string)] = atomic.LoadInt64(value.(*int64))
+		result.Errors[key.(ErrorKey).String()] = atomic.LoadInt64(value.(*int64))
 		return true
 	})
 
 	m.requestLatency.Range(func(key, value interface{}) bool {
-		if strings.HasSuffix(key.(string), "_bucket") {
-			bucket := value.(*LatencyBucket)
-			if bucket.count > 0 {
-				baseKey := strings.TrimSuffix(key.(string), "_bucket")
-				result.Latencies[baseKey] = bucket.sum / float64(bucket.count)
-			}
+		bucket := value.(*LatencyBucket)
+		bucket.mu.Lock()
+		if bucket.count > 0 {
+			result.Latencies[key.(RouteKey).String()] = bucket.sum / float64(bucket.count)
 		}
+		bucket.mu.Unlock()
 		return true
 	})
 
@@ -192,5 +317,39 @@ func (m *Metrics) ToJSON() *MetricsJSON {
 		return true
 	})
 
+	m.allocations.Range(func(key, value interface{}) bool {
+		if result.Allocations == nil {
+			result.Allocations = make(map[string]AllocJSON)
+		}
+		result.Allocations[key.(RouteKey).String()] = value.(*AllocStats).snapshot()
+		return true
+	})
+
+	m.writeCustomJSON(result)
+
 	return result
 }
+
+func sortedRouteKeys(store *sync.Map) []RouteKey {
+	var keys []RouteKey
+	store.Range(func(key, value interface{}) bool {
+		keys = append(keys, key.(RouteKey))
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	return keys
+}
+
+func (s *AllocStats) snapshot() AllocJSON {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		return AllocJSON{}
+	}
+	return AllocJSON{
+		Samples:    s.count,
+		AvgBytes:   float64(s.bytes) / float64(s.count),
+		AvgObjects: float64(s.objects) / float64(s.count),
+		MaxBytes:   s.maxBytes,
+	}
+}
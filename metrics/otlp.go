@@ -0,0 +1,279 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type OTLPConfig struct {
+	Endpoint    string
+	ServiceName string
+	Headers     map[string]string
+	Interval    time.Duration
+	Timeout     time.Duration
+	Client      *http.Client
+	Source      *Metrics
+}
+
+type OTLP struct {
+	*Metrics
+	cfg    OTLPConfig
+	start  time.Time
+	done   chan struct{}
+	closed sync.Once
+	wg     sync.WaitGroup
+	errMu  sync.Mutex
+	err    error
+}
+
+func NewOTLP(cfg *OTLPConfig) *OTLP {
+	c := OTLPConfig{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = "http://localhost:4318/v1/metrics"
+	}
+	if c.ServiceName == "" {
+		c.ServiceName = "fastrest"
+	}
+	if c.Interval <= 0 {
+		c.Interval = 15 * time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	if c.Client == nil {
+		c.Client = &http.Client{Timeout: c.Timeout}
+	}
+	if c.Source == nil {
+		c.Source = New()
+	}
+
+	o := &OTLP{Metrics: c.Source, cfg: c, start: c.Source.clock.Now(), done: make(chan struct{})}
+	o.wg.Add(1)
+	go o.loop()
+	return o
+}
+
+func (o *OTLP) loop() {
+	defer o.wg.Done()
+	ticker := time.NewTicker(o.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			o.setErr(o.Export(context.Background()))
+		case <-o.done:
+			return
+		}
+	}
+}
+
+func (o *OTLP) setErr(err error) {
+	o.errMu.Lock()
+	o.err = err
+	o.errMu.Unlock()
+}
+
+func (o *OTLP) LastError() error {
+	o.errMu.Lock()
+	defer o.errMu.Unlock()
+	return o.err
+}
+
+func (o *OTLP) Close() error {
+	var err error
+	o.closed.Do(func() {
+		close(o.done)
+		o.wg.Wait()
+		err = o.Export(context.Background())
+		o.setErr(err)
+	})
+	return err
+}
+
+func (o *OTLP) Export(ctx context.Context) error {
+	body, err := json.Marshal(o.payload(o.Snapshot()))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range o.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := o.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("metrics: otlp export failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type otlpAttr struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpPoint struct {
+	Attributes     []otlpAttr `json:"attributes,omitempty"`
+	StartTimeUnix  string     `json:"startTimeUnixNano,omitempty"`
+	TimeUnix       string     `json:"timeUnixNano"`
+	AsInt          *string    `json:"asInt,omitempty"`
+	AsDouble       *float64   `json:"asDouble,omitempty"`
+	Count          string     `json:"count,omitempty"`
+	Sum            *float64   `json:"sum,omitempty"`
+	BucketCounts   []string   `json:"bucketCounts,omitempty"`
+	ExplicitBounds []float64  `json:"explicitBounds,omitempty"`
+}
+
+type otlpData struct {
+	DataPoints             []otlpPoint `json:"dataPoints"`
+	AggregationTemporality int         `json:"aggregationTemporality,omitempty"`
+	IsMonotonic            bool        `json:"isMonotonic,omitempty"`
+}
+
+type otlpMetric struct {
+	Name      string    `json:"name"`
+	Unit      string    `json:"unit,omitempty"`
+	Sum       *otlpData `json:"sum,omitempty"`
+	Gauge     *otlpData `json:"gauge,omitempty"`
+	Histogram *otlpData `json:"histogram,omitempty"`
+}
+
+const otlpCumulative = 2
+
+func (o *OTLP) payload(s *Snapshot) map[string]interface{} {
+	start := strconv.FormatInt(o.start.UnixNano(), 10)
+	now := strconv.FormatInt(s.TakenAt.UnixNano(), 10)
+	intPoint := func(v int64, labels ...string) otlpPoint {
+		str := strconv.FormatInt(v, 10)
+		return otlpPoint{Attributes: otlpAttrs(labels...), StartTimeUnix: start, TimeUnix: now, AsInt: &str}
+	}
+	doublePoint := func(v float64, attrs []otlpAttr) otlpPoint {
+		return otlpPoint{Attributes: attrs, StartTimeUnix: start, TimeUnix: now, AsDouble: &v}
+	}
+
+	requests := &otlpData{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+	for k, v := range s.Requests {
+		requests.DataPoints = append(requests.DataPoints, intPoint(v, "http.method", k.Method, "http.route", k.Path, "http.status_code", strconv.Itoa(k.Status)))
+	}
+	errs := &otlpData{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+	for k, v := range s.Errors {
+		errs.DataPoints = append(errs.DataPoints, intPoint(v, "http.method", k.Method, "http.route", k.Path, "error.type", k.Type))
+	}
+	latency := &otlpData{AggregationTemporality: otlpCumulative}
+	for k, v := range s.Latencies {
+		sum := v.SumMs
+		latency.DataPoints = append(latency.DataPoints, otlpPoint{
+			Attributes:     otlpAttrs("http.method", k.Method, "http.route", k.Path),
+			StartTimeUnix:  start,
+			TimeUnix:       now,
+			Count:          strconv.FormatInt(v.Count, 10),
+			Sum:            &sum,
+			BucketCounts:   deltaBuckets(v.Buckets, v.Count),
+			ExplicitBounds: s.LatencyBuckets,
+		})
+	}
+	conns := &otlpData{DataPoints: []otlpPoint{intPoint(s.ActiveConns)}}
+
+	metrics := []otlpMetric{
+		{Name: "http.server.requests", Sum: requests},
+		{Name: "http.server.errors", Sum: errs},
+		{Name: "http.server.duration", Unit: "ms", Histogram: latency},
+		{Name: "http.server.active_connections", Gauge: conns},
+	}
+	metrics = append(metrics, customOTLP(s.Counters, func(v float64, attrs []otlpAttr) *otlpData {
+		return &otlpData{AggregationTemporality: otlpCumulative, IsMonotonic: true, DataPoints: []otlpPoint{doublePoint(v, attrs)}}
+	}, true)...)
+	metrics = append(metrics, customOTLP(s.Gauges, func(v float64, attrs []otlpAttr) *otlpData {
+		return &otlpData{DataPoints: []otlpPoint{doublePoint(v, attrs)}}
+	}, false)...)
+
+	return map[string]interface{}{
+		"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{"attributes": otlpAttrs("service.name", o.cfg.ServiceName)},
+			"scopeMetrics": []interface{}{map[string]interface{}{
+				"scope":   map[string]string{"name": "fastrest"},
+				"metrics": metrics,
+			}},
+		}},
+	}
+}
+
+func customOTLP(values map[string]float64, data func(float64, []otlpAttr) *otlpData, sum bool) []otlpMetric {
+	ids := make([]string, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var out []otlpMetric
+	for _, id := range ids {
+		name, attrs := parseCustomID(id)
+		m := otlpMetric{Name: name}
+		if sum {
+			m.Sum = data(values[id], attrs)
+		} else {
+			m.Gauge = data(values[id], attrs)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func parseCustomID(id string) (string, []otlpAttr) {
+	name, rest, ok := strings.Cut(id, "{")
+	if !ok {
+		return id, nil
+	}
+	var labels []string
+	for _, pair := range strings.Split(strings.TrimSuffix(rest, "}"), `",`) {
+		k, v, ok := strings.Cut(pair, `="`)
+		if ok {
+			labels = append(labels, k, strings.TrimSuffix(v, `"`))
+		}
+	}
+	return name, otlpAttrs(labels...)
+}
+
+func otlpAttrs(labels ...string) []otlpAttr {
+	attrs := make([]otlpAttr, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		a := otlpAttr{Key: labels[i]}
+		a.Value.StringValue = labels[i+1]
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+func deltaBuckets(cumulative []int64, total int64) []string {
+	out := make([]string, 0, len(cumulative)+1)
+	var prev int64
+	for _, c := range cumulative {
+		out = append(out, strconv.FormatInt(c-prev, 10))
+		prev = c
+	}
+	return append(out, strconv.FormatInt(total-prev, 10))
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+type Recorder interface {
+	IncRequestTotal(method, path string, status int)
+	ObserveLatency(method, path string, status int, duration time.Duration, traceID string)
+	IncError(method, path, errorType string)
+	IncActiveConns()
+	DecActiveConns()
+}
+
+var _ Recorder = (*Metrics)(nil)
+
+type multiRecorder []Recorder
+
+func Multi(recorders ...Recorder) Recorder {
+	var out multiRecorder
+	for _, r := range recorders {
+		if r == nil {
+			continue
+		}
+		if m, ok := r.(*Metrics); ok && m == nil {
+			continue
+		}
+		out = append(out, r)
+	}
+	switch len(out) {
+	case 0:
+		return nil
+	case 1:
+		return out[0]
+	}
+	return out
+}
+
+func (m multiRecorder) IncRequestTotal(method, path string, status int) {
+	for _, r := range m {
+		r.IncRequestTotal(method, path, status)
+	}
+}
+
+func (m multiRecorder) ObserveLatency(method, path string, status int, duration time.Duration, traceID string) {
+	for _, r := range m {
+		r.ObserveLatency(method, path, status, duration, traceID)
+	}
+}
+
+func (m multiRecorder) IncError(method, path, errorType string) {
+	for _, r := range m {
+		r.IncError(method, path, errorType)
+	}
+}
+
+func (m multiRecorder) IncActiveConns() {
+	for _, r := range m {
+		r.IncActiveConns()
+	}
+}
+
+func (m multiRecorder) DecActiveConns() {
+	for _, r := range m {
+		r.DecActiveConns()
+	}
+}
+
+func (m multiRecorder) Close() error {
+	var errs []error
+	for _, r := range m {
+		if c, ok := r.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
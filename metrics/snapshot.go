@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type LatencySnapshot struct {
+	Count   int64
+	SumMs   float64
+	Buckets []int64
+}
+
+type Snapshot struct {
+	TakenAt        time.Time
+	Requests       map[RequestKey]int64
+	Errors         map[ErrorKey]int64
+	Latencies      map[RouteKey]LatencySnapshot
+	Logs           map[string]int64
+	Counters       map[string]float64
+	Gauges         map[string]float64
+	Histograms     map[string]HistogramJSON
+	ActiveConns    int64
+	RequestRate1m  float64
+	RequestRate5m  float64
+	ErrorRate1m    float64
+	ErrorRate5m    float64
+	LatencyBuckets []float64
+	UptimeSeconds  float64
+	TotalRequests  int64
+	TotalErrors    int64
+}
+
+func (m *Metrics) Snapshot() *Snapshot {
+	now := m.clock.Now()
+	s := &Snapshot{
+		TakenAt:        now,
+		Requests:       make(map[RequestKey]int64),
+		Errors:         make(map[ErrorKey]int64),
+		Latencies:      make(map[RouteKey]LatencySnapshot),
+		Logs:           make(map[string]int64),
+		ActiveConns:    atomic.LoadInt64(&m.activeConns),
+		RequestRate1m:  m.requestWindow.rate(now, time.Minute),
+		RequestRate5m:  m.requestWindow.rate(now, 5*time.Minute),
+		ErrorRate1m:    m.errorWindow.rate(now, time.Minute),
+		ErrorRate5m:    m.errorWindow.rate(now, 5*time.Minute),
+		LatencyBuckets: m.Buckets(),
+		UptimeSeconds:  m.clock.Since(m.startTime).Seconds(),
+	}
+
+	m.requestTotal.Range(func(key, value interface{}) bool {
+		n := atomic.LoadInt64(value.(*int64))
+		s.Requests[key.(RequestKey)] = n
+		s.TotalRequests += n
+		return true
+	})
+	m.errorTotal.Range(func(key, value interface{}) bool {
+		n := atomic.LoadInt64(value.(*int64))
+		s.Errors[key.(ErrorKey)] = n
+		s.TotalErrors += n
+		return true
+	})
+	m.requestLatency.Range(func(key, value interface{}) bool {
+		bucket := value.(*LatencyBucket)
+		bucket.mu.Lock()
+		s.Latencies[key.(RouteKey)] = LatencySnapshot{
+			Count:   bucket.count,
+			SumMs:   bucket.sum,
+			Buckets: append([]int64{}, bucket.counts...),
+		}
+		bucket.mu.Unlock()
+		return true
+	})
+	m.logCount.Range(func(key, value interface{}) bool {
+		s.Logs[key.(string)] = atomic.LoadInt64(value.(*int64))
+		return true
+	})
+
+	custom := &MetricsJSON{}
+	m.writeCustomJSON(custom)
+	s.Counters, s.Gauges, s.Histograms = custom.Counters, custom.Gauges, custom.Histograms
+	return s
+}
+
+func (s *Snapshot) Sub(prev *Snapshot) *Snapshot {
+	d := *s
+	d.Requests = make(map[RequestKey]int64, len(s.Requests))
+	for k, v := range s.Requests {
+		d.Requests[k] = v - prev.Requests[k]
+	}
+	d.Errors = make(map[ErrorKey]int64, len(s.Errors))
+	for k, v := range s.Errors {
+		d.Errors[k] = v - prev.Errors[k]
+	}
+	d.Logs = make(map[string]int64, len(s.Logs))
+	for k, v := range s.Logs {
+		d.Logs[k] = v - prev.Logs[k]
+	}
+	d.Counters = make(map[string]float64, len(s.Counters))
+	for k, v := range s.Counters {
+		d.Counters[k] = v - prev.Counters[k]
+	}
+	d.TotalRequests = s.TotalRequests - prev.TotalRequests
+	d.TotalErrors = s.TotalErrors - prev.TotalErrors
+	return &d
+}
+
+func (m *Metrics) Reset() {
+	drop := func(store *sync.Map) {
+		store.Range(func(key, _ interface{}) bool {
+			store.Delete(key)
+			return true
+		})
+	}
+	drop(&m.requestTotal)
+	drop(&m.requestLatency)
+	drop(&m.requestDuration)
+	drop(&m.errorTotal)
+	drop(&m.logCount)
+	drop(&m.allocations)
+	m.requestWindow.reset()
+	m.errorWindow.reset()
+
+	m.custom.Range(func(_, value interface{}) bool {
+		switch metric := value.(type) {
+		case *Counter:
+			atomic.StoreUint64(&metric.bits, 0)
+		case *Histogram:
+			metric.reset()
+		}
+		return true
+	})
+}
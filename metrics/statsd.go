@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const statsdMaxPacket = 1432
+
+type StatsDConfig struct {
+	Addr          string
+	Prefix        string
+	Tags          bool
+	GlobalTags    []string
+	FlushInterval time.Duration
+}
+
+type StatsD struct {
+	cfg    StatsDConfig
+	conn   net.Conn
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	conns  int64
+	done   chan struct{}
+	closed sync.Once
+	wg     sync.WaitGroup
+}
+
+func NewStatsD(cfg *StatsDConfig) (*StatsD, error) {
+	c := StatsDConfig{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Addr == "" {
+		c.Addr = "127.0.0.1:8125"
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second
+	}
+	if c.Prefix != "" && !strings.HasSuffix(c.Prefix, ".") {
+		c.Prefix += "."
+	}
+
+	conn, err := net.Dial("udp", c.Addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &StatsD{cfg: c, conn: conn, done: make(chan struct{})}
+	s.wg.Add(1)
+	go s.flushLoop()
+	return s, nil
+}
+
+func NewDatadog(addr string, globalTags ...string) (*StatsD, error) {
+	return NewStatsD(&StatsDConfig{Addr: addr, Tags: true, GlobalTags: globalTags})
+}
+
+func (s *StatsD) IncRequestTotal(method, path string, status int) {
+	s.send("http.requests", "1|c", "method", method, "path", path, "status", strconv.Itoa(status))
+}
+
+func (s *StatsD) ObserveLatency(method, path string, status int, duration time.Duration, traceID string) {
+	ms := strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', 3, 64)
+	s.send("http.request.duration", ms+"|ms", "method", method, "path", path, "status", StatusClass(status))
+}
+
+func (s *StatsD) IncError(method, path, errorType string) {
+	s.send("http.errors", "1|c", "method", method, "path", path, "type", errorType)
+}
+
+func (s *StatsD) IncActiveConns() {
+	s.send("active_connections", strconv.FormatInt(atomic.AddInt64(&s.conns, 1), 10)+"|g")
+}
+
+func (s *StatsD) DecActiveConns() {
+	s.send("active_connections", strconv.FormatInt(atomic.AddInt64(&s.conns, -1), 10)+"|g")
+}
+
+func (s *StatsD) Count(name string, value int64, labels ...string) {
+	s.send(name, strconv.FormatInt(value, 10)+"|c", labels...)
+}
+
+func (s *StatsD) Gauge(name string, value float64, labels ...string) {
+	s.send(name, strconv.FormatFloat(value, 'f', -1, 64)+"|g", labels...)
+}
+
+func (s *StatsD) Timing(name string, d time.Duration, labels ...string) {
+	ms := strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+	s.send(name, ms+"|ms", labels...)
+}
+
+func (s *StatsD) send(name, value string, labels ...string) {
+	var line strings.Builder
+	line.WriteString(s.cfg.Prefix)
+	line.WriteString(name)
+	if !s.cfg.Tags {
+		for i := 1; i < len(labels); i += 2 {
+			line.WriteByte('.')
+			line.WriteString(sanitizeStatsD(labels[i]))
+		}
+	}
+	line.WriteByte(':')
+	line.WriteString(value)
+	if s.cfg.Tags && (len(labels) >= 2 || len(s.cfg.GlobalTags) > 0) {
+		line.WriteString("|#")
+		first := true
+		for _, tag := range s.cfg.GlobalTags {
+			if !first {
+				line.WriteByte(',')
+			}
+			line.WriteString(tag)
+			first = false
+		}
+		for i := 0; i+1 < len(labels); i += 2 {
+			if !first {
+				line.WriteByte(',')
+			}
+			line.WriteString(labels[i])
+			line.WriteByte(':')
+			line.WriteString(strings.ReplaceAll(labels[i+1], ",", "_"))
+			first = false
+		}
+	}
+	line.WriteByte('\n')
+
+	s.mu.Lock()
+	if s.buf.Len()+line.Len() > statsdMaxPacket {
+		s.flushLocked()
+	}
+	s.buf.WriteString(line.String())
+	s.mu.Unlock()
+}
+
+func (s *StatsD) Flush() {
+	s.mu.Lock()
+	s.flushLocked()
+	s.mu.Unlock()
+}
+
+func (s *StatsD) flushLocked() {
+	if s.buf.Len() == 0 {
+		return
+	}
+	s.conn.Write(bytes.TrimSuffix(s.buf.Bytes(), []byte("\n")))
+	s.buf.Reset()
+}
+
+func (s *StatsD) flushLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Flush()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *StatsD) Close() error {
+	var err error
+	s.closed.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+		s.Flush()
+		err = s.conn.Close()
+	})
+	return err
+}
+
+func sanitizeStatsD(v string) string {
+	v = strings.Trim(v, "/")
+	if v == "" {
+		return "root"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, v)
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+const windowSeconds = 300
+
+type rateWindow struct {
+	mu     sync.Mutex
+	counts [windowSeconds]int64
+	stamps [windowSeconds]int64
+}
+
+func (w *rateWindow) add(now time.Time) {
+	sec := now.Unix()
+	i := sec % windowSeconds
+	w.mu.Lock()
+	if w.stamps[i] != sec {
+		w.stamps[i] = sec
+		w.counts[i] = 0
+	}
+	w.counts[i]++
+	w.mu.Unlock()
+}
+
+func (w *rateWindow) rate(now time.Time, window time.Duration) float64 {
+	seconds := int64(window / time.Second)
+	if seconds <= 0 || seconds > windowSeconds {
+		seconds = windowSeconds
+	}
+	sec := now.Unix()
+
+	var total int64
+	w.mu.Lock()
+	for i := range w.counts {
+		if age := sec - w.stamps[i]; age >= 0 && age < seconds {
+			total += w.counts[i]
+		}
+	}
+	w.mu.Unlock()
+	return float64(total) / float64(seconds)
+}
+
+func (w *rateWindow) reset() {
+	w.mu.Lock()
+	w.counts = [windowSeconds]int64{}
+	w.stamps = [windowSeconds]int64{}
+	w.mu.Unlock()
+}
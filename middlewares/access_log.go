@@ -0,0 +1,145 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"fastrest/context"
+)
+
+var DefaultAccessLogFields = []string{
+	"time", "request_id", "method", "path", "status", "latency_ms",
+	"bytes_in", "bytes_out", "ip", "user_agent",
+}
+
+type AccessLogConfig struct {
+	Output      io.Writer
+	Fields      []string
+	SkipPaths   []string
+	SampleRate  float64
+	SampleRates map[string]float64
+	Redactor    *Redactor
+}
+
+func AccessLog(cfg *AccessLogConfig) context.Middleware {
+	c := AccessLogConfig{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+	if len(c.Fields) == 0 {
+		c.Fields = DefaultAccessLogFields
+	}
+	if c.SampleRate <= 0 || c.SampleRate > 1 {
+		c.SampleRate = 1
+	}
+	if c.Redactor == nil {
+		c.Redactor = DefaultRedactor()
+	}
+
+	var mu sync.Mutex
+	return func(next context.Handler) context.Handler {
+		return func(ctx *context.Ctx) error {
+			path := ctx.Path()
+			if skipPath(path, c.SkipPaths) {
+				return next(ctx)
+			}
+
+			start := ctx.Now()
+			err := next(ctx)
+			latency := ctx.Clock().Since(start)
+
+			status := context.ErrorStatus(ctx, err)
+			if err == nil && status < 500 && !sampled(path, c.SampleRate, c.SampleRates) {
+				return err
+			}
+
+			var buf bytes.Buffer
+			buf.WriteByte('{')
+			for i, field := range c.Fields {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				key, _ := json.Marshal(field)
+				value, _ := json.Marshal(accessLogValue(ctx, c.Redactor, field, path, status, start, latency, err))
+				buf.Write(key)
+				buf.WriteByte(':')
+				buf.Write(value)
+			}
+			buf.WriteString("}\n")
+
+			mu.Lock()
+			c.Output.Write(buf.Bytes())
+			mu.Unlock()
+
+			return err
+		}
+	}
+}
+
+func accessLogValue(c *context.Ctx, r *Redactor, field, path string, status int, start time.Time, latency time.Duration, err error) interface{} {
+	switch field {
+	case "time":
+		return start.UTC().Format(time.RFC3339Nano)
+	case "request_id":
+		return c.RequestID()
+	case "method":
+		return c.Method()
+	case "path":
+		return path
+	case "query":
+		return r.Query(string(c.QueryArgs().QueryString()))
+	case "status":
+		return status
+	case "latency_ms":
+		return float64(latency) / float64(time.Millisecond)
+	case "bytes_in":
+		return len(c.Request.Body())
+	case "bytes_out":
+		return len(c.Response.Body())
+	case "ip":
+		return c.IP()
+	case "user_agent":
+		return string(c.UserAgent())
+	case "referer":
+		return string(c.Referer())
+	case "error":
+		if err != nil {
+			return err.Error()
+		}
+		return nil
+	default:
+		return r.Header(field, c.Get(field))
+	}
+}
+
+func skipPath(path string, skip []string) bool {
+	for _, p := range skip {
+		if matchPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func sampled(path string, rate float64, rates map[string]float64) bool {
+	longest := -1
+	for prefix, r := range rates {
+		if len(prefix) > longest && matchPrefix(path, prefix) {
+			rate, longest = r, len(prefix)
+		}
+	}
+	return rate >= 1 || rand.Float64() < rate
+}
+
+func matchPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
@@ -74,6 +74,7 @@ func BasicAuth(validator BasicAuthValidator) context.Middleware {
 
 			c.SetAuth(&context.AuthInfo{
 				Type:     "basic",
+				Subject:  username,
 				Username: username,
 				Password: password,
 				Valid:    true,
@@ -202,3 +203,35 @@ func Auth(config *AuthConfig) context.Middleware {
 		}
 	}
 }
+
+func RequireScopes(scopes ...string) context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			auth, err := c.MustAuth()
+			if err != nil {
+				return c.Unauthorized("authentication required")
+			}
+			for _, scope := range scopes {
+				if !auth.HasScope(scope) {
+					return c.Forbidden("missing scope: " + scope)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+func RequireRoles(roles ...string) context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			auth, err := c.MustAuth()
+			if err != nil {
+				return c.Unauthorized("authentication required")
+			}
+			if !auth.HasAnyRole(roles...) {
+				return c.Forbidden("missing role")
+			}
+			return next(c)
+		}
+	}
+}
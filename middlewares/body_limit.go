@@ -0,0 +1,18 @@
+package middlewares
+
+import (
+	"fmt"
+
+	"fastrest/context"
+)
+
+func BodyLimit(limit int) context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			if limit > 0 && (c.Request.Header.ContentLength() > limit || len(c.Body()) > limit) {
+				return c.PayloadTooLarge(fmt.Sprintf("request body exceeds %d bytes", limit))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package middlewares
+
+import (
+	"container/list"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fastrest/context"
+	"fastrest/pkg/clock"
+)
+
+const CacheHeader = "X-Cache"
+
+type CachedResponse struct {
+	Status   int
+	Headers  map[string]string
+	Body     []byte
+	Tags     []string
+	StoredAt time.Time
+}
+
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, entry *CachedResponse, ttl time.Duration)
+	Delete(key string)
+}
+
+type CacheOption func(*cacheConfig)
+
+type cacheConfig struct {
+	store       CacheStore
+	key         func(c *context.Ctx) string
+	invalidator *CacheInvalidator
+	bypass      []string
+}
+
+var DefaultCacheBypassHeaders = []string{"Authorization", "Cookie", "X-API-Key", "X-Tenant-ID"}
+
+func WithCacheStore(store CacheStore) CacheOption {
+	return func(cfg *cacheConfig) {
+		cfg.store = store
+	}
+}
+
+func WithCacheKey(key func(c *context.Ctx) string) CacheOption {
+	return func(cfg *cacheConfig) {
+		cfg.key = key
+	}
+}
+
+func WithCacheBypassHeaders(headers ...string) CacheOption {
+	return func(cfg *cacheConfig) {
+		cfg.bypass = append(cfg.bypass, headers...)
+	}
+}
+
+func WithCacheInvalidator(inv *CacheInvalidator) CacheOption {
+	return func(cfg *cacheConfig) {
+		cfg.invalidator = inv
+	}
+}
+
+var uncachedHeaders = map[string]bool{
+	"Content-Length": true,
+	"Connection":     true,
+	"Date":           true,
+	"Server":         true,
+	"Trailer":        true,
+	CacheHeader:      true,
+	RequestIDHeader:  true,
+}
+
+func Cache(ttl time.Duration, opts ...CacheOption) context.Middleware {
+	cfg := &cacheConfig{
+		key:         defaultCacheKey,
+		invalidator: DefaultCacheInvalidator(),
+		bypass:      append([]string{}, DefaultCacheBypassHeaders...),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.store == nil {
+		cfg.store = NewLRUStore(1024)
+	}
+	tagged := cfg.invalidator.register(cfg.store)
+
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			if c.Method() != "GET" {
+				return next(c)
+			}
+			if personalized(c, cfg.bypass) {
+				err := next(c)
+				c.Set(CacheHeader, "BYPASS")
+				return err
+			}
+
+			reqDirectives := parseCacheControl(c.Get("Cache-Control"))
+			base := cfg.key(c)
+			key := base
+			if vary, ok := cfg.store.Get(varyCacheKey(base)); ok {
+				key = base + varySuffix(c, vary.Headers["Vary"])
+			}
+
+			if !reqDirectives.noCache && !reqDirectives.noStore {
+				if entry, ok := cfg.store.Get(key); ok {
+					writeCached(c, entry)
+					return nil
+				}
+				tagged.missed(key)
+			}
+
+			since := cfg.invalidator.begin()
+			defer cfg.invalidator.end(since)
+			if err := next(c); err != nil {
+				return err
+			}
+
+			status := c.Response.StatusCode()
+			if reqDirectives.noStore || status < 200 || status >= 300 || status == 206 {
+				c.Set(CacheHeader, "BYPASS")
+				return nil
+			}
+
+			entryTTL, ok := responseTTL(c, ttl)
+			if !ok {
+				c.Set(CacheHeader, "BYPASS")
+				return nil
+			}
+
+			vary := normalizeVary(string(c.Response.Header.Peek("Vary")))
+			if vary == "*" {
+				c.Set(CacheHeader, "BYPASS")
+				return nil
+			}
+			key = base
+			if vary != "" {
+				cfg.store.Set(varyCacheKey(base), &CachedResponse{Headers: map[string]string{"Vary": vary}, StoredAt: c.Now()}, entryTTL)
+				key = base + varySuffix(c, vary)
+			}
+
+			entry := captureResponse(c)
+			stored := cfg.invalidator.store(tagged, key, entry, since, func() {
+				cfg.store.Set(key, entry, entryTTL)
+			})
+			if !stored {
+				c.Set(CacheHeader, "BYPASS")
+				return nil
+			}
+			c.Set(CacheHeader, "MISS")
+			return nil
+		}
+	}
+}
+
+func personalized(c *context.Ctx, headers []string) bool {
+	if c.GetAuth() != nil || c.Tenant() != "" {
+		return true
+	}
+	for _, h := range headers {
+		if len(c.Request.Header.Peek(h)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func varyCacheKey(base string) string {
+	return "vary\x00" + base
+}
+
+func normalizeVary(value string) string {
+	var names []string
+	for _, part := range strings.Split(value, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+		if name == "*" {
+			return "*"
+		}
+		names = append(names, http.CanonicalHeaderKey(name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func varySuffix(c *context.Ctx, vary string) string {
+	if vary == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, name := range strings.Split(vary, ",") {
+		b.WriteString("\x00")
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(c.Get(name))
+	}
+	return b.String()
+}
+
+func defaultCacheKey(c *context.Ctx) string {
+	key := c.Method() + " " + strings.ToLower(string(c.Host())) + c.Path()
+	if query := c.QueryArgs().QueryString(); len(query) > 0 {
+		key += "?" + string(query)
+	}
+	return key
+}
+
+func responseTTL(c *context.Ctx, ttl time.Duration) (time.Duration, bool) {
+	if len(c.Response.Header.Peek("Set-Cookie")) > 0 {
+		return 0, false
+	}
+
+	directives := parseCacheControl(string(c.Response.Header.Peek("Cache-Control")))
+	if !directives.public || directives.noStore || directives.private {
+		return 0, false
+	}
+	if directives.maxAge >= 0 {
+		ttl = time.Duration(directives.maxAge) * time.Second
+	}
+	return ttl, ttl > 0
+}
+
+func captureResponse(c *context.Ctx) *CachedResponse {
+	entry := &CachedResponse{
+		Status:   c.Response.StatusCode(),
+		Headers:  make(map[string]string),
+		Body:     append([]byte{}, c.Response.Body()...),
+		StoredAt: c.Now(),
+	}
+	if tags, ok := context.CacheTagsKey.Get(c); ok {
+		entry.Tags = append([]string{}, tags...)
+	}
+	for k, v := range c.Response.Header.All() {
+		key := string(k)
+		if !uncachedHeaders[key] && !strings.HasPrefix(key, "Access-Control-") {
+			entry.Headers[key] = string(v)
+		}
+	}
+	return entry
+}
+
+func writeCached(c *context.Ctx, entry *CachedResponse) {
+	writeStored(c, entry)
+	c.Set("Age", strconv.Itoa(int(c.Clock().Since(entry.StoredAt).Seconds())))
+	c.Set(CacheHeader, "HIT")
+}
+
+func writeStored(c *context.Ctx, entry *CachedResponse) {
+	for k, v := range entry.Headers {
+		if k == "Content-Type" || len(c.Response.Header.Peek(k)) == 0 {
+			c.Set(k, v)
+		}
+	}
+	c.Response.SetStatusCode(entry.Status)
+	c.Response.SetBody(entry.Body)
+}
+
+type cacheControl struct {
+	noCache bool
+	noStore bool
+	private bool
+	public  bool
+	maxAge  int
+}
+
+func parseCacheControl(value string) cacheControl {
+	cc := cacheControl{maxAge: -1}
+	sMaxAge := -1
+	for _, part := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(strings.ToLower(part)), "=")
+		switch name {
+		case "no-cache":
+			cc.noCache = true
+		case "no-store":
+			cc.noStore = true
+		case "private":
+			cc.private = true
+		case "public":
+			cc.public = true
+		case "max-age":
+			if n, err := strconv.Atoi(arg); err == nil {
+				cc.maxAge = n
+			}
+		case "s-maxage":
+			if n, err := strconv.Atoi(arg); err == nil {
+				sMaxAge = n
+			}
+		}
+	}
+	if sMaxAge >= 0 {
+		cc.maxAge = sMaxAge
+	}
+	return cc
+}
+
+type LRUStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+	clock    clock.Clock
+	evict    []func(key string)
+}
+
+type lruEntry struct {
+	key     string
+	value   *CachedResponse
+	expires time.Time
+}
+
+func NewLRUStore(capacity int) *LRUStore {
+	if capacity <= 0 {
+		capacity = 1024
+	}
+	return &LRUStore{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		clock:    clock.System,
+	}
+}
+
+func (s *LRUStore) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+func (s *LRUStore) OnEvict(fn func(key string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evict = append(s.evict, fn)
+}
+
+func (s *LRUStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	el, ok := s.items[key]
+	if !ok {
+		s.mu.Unlock()
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if s.clock.Now().After(entry.expires) {
+		s.order.Remove(el)
+		delete(s.items, key)
+		evict := s.evict
+		s.mu.Unlock()
+		notifyEvicted(evict, key)
+		return nil, false
+	}
+	s.order.MoveToFront(el)
+	s.mu.Unlock()
+	return entry.value, true
+}
+
+func (s *LRUStore) Set(key string, value *CachedResponse, ttl time.Duration) {
+	s.mu.Lock()
+	expires := s.clock.Now().Add(ttl)
+	if el, ok := s.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+		s.order.MoveToFront(el)
+		s.mu.Unlock()
+		return
+	}
+
+	s.items[key] = s.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	var evicted []string
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*lruEntry).key)
+		evicted = append(evicted, oldest.Value.(*lruEntry).key)
+	}
+	evict := s.evict
+	s.mu.Unlock()
+	notifyEvicted(evict, evicted...)
+}
+
+func (s *LRUStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.order.Remove(el)
+		delete(s.items, key)
+	}
+}
+
+func (s *LRUStore) Purge() {
+	s.mu.Lock()
+	var evicted []string
+	if len(s.evict) > 0 {
+		evicted = make([]string, 0, len(s.items))
+		for key := range s.items {
+			evicted = append(evicted, key)
+		}
+	}
+	s.items = make(map[string]*list.Element)
+	s.order.Init()
+	evict := s.evict
+	s.mu.Unlock()
+	notifyEvicted(evict, evicted...)
+}
+
+func notifyEvicted(evict []func(key string), keys ...string) {
+	for _, key := range keys {
+		for _, fn := range evict {
+			fn(key)
+		}
+	}
+}
+
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
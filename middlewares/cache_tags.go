@@ -0,0 +1,204 @@
+package middlewares
+
+import (
+	"sync"
+)
+
+type EvictNotifier interface {
+	OnEvict(fn func(key string))
+}
+
+type CacheInvalidator struct {
+	mu       sync.Mutex
+	stores   []*taggedStore
+	gen      uint64
+	tagGen   map[string]uint64
+	inflight map[uint64]int
+}
+
+type taggedStore struct {
+	store    CacheStore
+	notifies bool
+	mu       sync.Mutex
+	tags     map[string]map[string]struct{}
+	keyTags  map[string][]string
+}
+
+var defaultCacheInvalidator = NewCacheInvalidator()
+
+func DefaultCacheInvalidator() *CacheInvalidator {
+	return defaultCacheInvalidator
+}
+
+func NewCacheInvalidator() *CacheInvalidator {
+	return &CacheInvalidator{
+		tagGen:   make(map[string]uint64),
+		inflight: make(map[uint64]int),
+	}
+}
+
+func (inv *CacheInvalidator) Invalidate(tags ...string) int {
+	inv.mu.Lock()
+	inv.gen++
+	for _, tag := range tags {
+		inv.tagGen[tag] = inv.gen
+	}
+	inv.prune()
+	stores := append([]*taggedStore{}, inv.stores...)
+	inv.mu.Unlock()
+
+	purged := 0
+	for _, ts := range stores {
+		keys := ts.take(tags)
+		for _, key := range keys {
+			ts.store.Delete(key)
+		}
+		purged += len(keys)
+	}
+	return purged
+}
+
+func (inv *CacheInvalidator) Purge() {
+	inv.mu.Lock()
+	stores := append([]*taggedStore{}, inv.stores...)
+	inv.mu.Unlock()
+
+	for _, ts := range stores {
+		if p, ok := ts.store.(interface{ Purge() }); ok {
+			p.Purge()
+		}
+		ts.mu.Lock()
+		ts.tags = make(map[string]map[string]struct{})
+		ts.keyTags = make(map[string][]string)
+		ts.mu.Unlock()
+	}
+}
+
+func (inv *CacheInvalidator) register(store CacheStore) *taggedStore {
+	ts := &taggedStore{
+		store:   store,
+		tags:    make(map[string]map[string]struct{}),
+		keyTags: make(map[string][]string),
+	}
+	if n, ok := store.(EvictNotifier); ok {
+		n.OnEvict(ts.evicted)
+		ts.notifies = true
+	}
+
+	inv.mu.Lock()
+	inv.stores = append(inv.stores, ts)
+	inv.mu.Unlock()
+	return ts
+}
+
+func (inv *CacheInvalidator) begin() uint64 {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.inflight[inv.gen]++
+	return inv.gen
+}
+
+func (inv *CacheInvalidator) end(since uint64) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	if inv.inflight[since]--; inv.inflight[since] <= 0 {
+		delete(inv.inflight, since)
+	}
+	if len(inv.inflight) == 0 && len(inv.tagGen) > 0 {
+		clear(inv.tagGen)
+	}
+}
+
+func (inv *CacheInvalidator) prune() {
+	oldest := inv.gen
+	for since := range inv.inflight {
+		oldest = min(oldest, since)
+	}
+	for tag, gen := range inv.tagGen {
+		if gen <= oldest {
+			delete(inv.tagGen, tag)
+		}
+	}
+}
+
+func (inv *CacheInvalidator) stale(tags []string, since uint64) bool {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	for _, tag := range tags {
+		if inv.tagGen[tag] > since {
+			return true
+		}
+	}
+	return false
+}
+
+func (inv *CacheInvalidator) store(ts *taggedStore, key string, entry *CachedResponse, since uint64, set func()) bool {
+	if inv.stale(entry.Tags, since) {
+		return false
+	}
+	set()
+	ts.index(key, entry.Tags)
+	if len(entry.Tags) > 0 && inv.stale(entry.Tags, since) {
+		ts.store.Delete(key)
+		ts.evicted(key)
+		return false
+	}
+	return true
+}
+
+func (ts *taggedStore) index(key string, tags []string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.forget(key)
+	if len(tags) == 0 {
+		return
+	}
+	ts.keyTags[key] = tags
+	for _, tag := range tags {
+		keys := ts.tags[tag]
+		if keys == nil {
+			keys = make(map[string]struct{})
+			ts.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (ts *taggedStore) take(tags []string) []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var keys []string
+	for _, tag := range tags {
+		for key := range ts.tags[tag] {
+			keys = append(keys, key)
+			ts.forget(key)
+		}
+	}
+	return keys
+}
+
+func (ts *taggedStore) evicted(key string) {
+	ts.mu.Lock()
+	ts.forget(key)
+	ts.mu.Unlock()
+}
+
+func (ts *taggedStore) missed(key string) {
+	if !ts.notifies {
+		ts.evicted(key)
+	}
+}
+
+func (ts *taggedStore) forget(key string) {
+	for _, tag := range ts.keyTags[key] {
+		if keys := ts.tags[tag]; keys != nil {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(ts.tags, tag)
+			}
+		}
+	}
+	delete(ts.keyTags, key)
+}
@@ -0,0 +1,112 @@
+package middlewares
+
+import (
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+	"fastrest/metrics"
+)
+
+type ConcurrencyOption func(*concurrencyConfig)
+
+type concurrencyConfig struct {
+	name       string
+	metrics    *metrics.Metrics
+	retryAfter time.Duration
+}
+
+func WithConcurrencyName(name string) ConcurrencyOption {
+	return func(cfg *concurrencyConfig) {
+		cfg.name = name
+	}
+}
+
+func WithConcurrencyMetrics(m *metrics.Metrics) ConcurrencyOption {
+	return func(cfg *concurrencyConfig) {
+		cfg.metrics = m
+	}
+}
+
+func WithRetryAfter(d time.Duration) ConcurrencyOption {
+	return func(cfg *concurrencyConfig) {
+		cfg.retryAfter = d
+	}
+}
+
+func Concurrency(max, queueLen int, timeout time.Duration, opts ...ConcurrencyOption) context.Middleware {
+	cfg := &concurrencyConfig{name: "default", retryAfter: time.Second}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if max <= 0 {
+		max = 1
+	}
+	if queueLen < 0 {
+		queueLen = 0
+	}
+
+	slots := make(chan struct{}, max)
+	var queued atomic.Int64
+	retryAfter := strconv.Itoa(int(math.Ceil(cfg.retryAfter.Seconds())))
+	inFlight := cfg.metrics.Gauge("concurrency_in_flight", "limiter", cfg.name)
+	waiting := cfg.metrics.Gauge("concurrency_queued", "limiter", cfg.name)
+
+	reject := func(c *context.Ctx, reason string) error {
+		cfg.metrics.Counter("concurrency_rejected_total", "limiter", cfg.name, "reason", reason).Inc()
+		c.Set("Retry-After", retryAfter)
+		return c.SendError(constant.StatusServiceUnavailable, "server is busy")
+	}
+
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			select {
+			case slots <- struct{}{}:
+			default:
+				if queued.Add(1) > int64(queueLen) {
+					queued.Add(-1)
+					return reject(c, "queue_full")
+				}
+				waiting.Inc()
+				acquired := wait(c, slots, timeout)
+				queued.Add(-1)
+				waiting.Dec()
+				if !acquired {
+					return reject(c, "timeout")
+				}
+			}
+
+			inFlight.Inc()
+			defer func() {
+				inFlight.Dec()
+				<-slots
+			}()
+			return next(c)
+		}
+	}
+}
+
+func wait(c *context.Ctx, slots chan struct{}, timeout time.Duration) bool {
+	if timeout <= 0 {
+		select {
+		case slots <- struct{}{}:
+			return true
+		case <-c.Context().Done():
+			return false
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-c.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"fastrest/context"
+	"fastrest/openapi"
+)
+
+type ContractReporter func(c *context.Ctx, mismatches []openapi.Mismatch)
+
+func Contract(doc *openapi.Document, reporter ContractReporter) context.Middleware {
+	if reporter == nil {
+		reporter = logContractMismatches
+	}
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			method := c.Method()
+			path := c.Path()
+
+			req := &openapi.Request{
+				Method:      method,
+				Path:        path,
+				Query:       make(map[string]string),
+				Headers:     make(map[string]string),
+				ContentType: string(c.Request.Header.ContentType()),
+				Body:        c.Body(),
+			}
+			c.QueryArgs().VisitAll(func(key, value []byte) {
+				req.Query[string(key)] = string(value)
+			})
+			c.Request.Header.VisitAll(func(key, value []byte) {
+				req.Headers[string(key)] = string(value)
+			})
+
+			mismatches := doc.ValidateRequest(req)
+
+			err := next(c)
+			if err == nil {
+				status := c.Response.StatusCode()
+				if status == 0 {
+					status = 200
+				}
+				mismatches = append(mismatches, doc.ValidateResponse(method, path, &openapi.ResponseData{
+					Status:      status,
+					ContentType: string(c.Response.Header.ContentType()),
+					Body:        c.Response.Body(),
+				})...)
+			}
+
+			if len(mismatches) > 0 {
+				reporter(c, mismatches)
+			}
+			return err
+		}
+	}
+}
+
+func logContractMismatches(c *context.Ctx, mismatches []openapi.Mismatch) {
+	for _, m := range mismatches {
+		c.GetLogger().Warn("contract mismatch",
+			"method", m.Method,
+			"path", m.Path,
+			"location", m.Location,
+			"message", m.Message)
+	}
+}
@@ -0,0 +1,108 @@
+package middlewares
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+var DefaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+type CORSConfig struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+func CORS(cfg *CORSConfig) context.Middleware {
+	c := CORSConfig{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if len(c.AllowOrigins) == 0 {
+		c.AllowOrigins = []string{"*"}
+	}
+	if c.AllowCredentials {
+		for _, o := range c.AllowOrigins {
+			if o == "*" {
+				panic("middlewares: CORS AllowCredentials requires explicit AllowOrigins, not \"*\"")
+			}
+		}
+	}
+	routeMethods := len(c.AllowMethods) == 0
+	if routeMethods {
+		c.AllowMethods = DefaultCORSMethods
+	}
+
+	methods := strings.Join(c.AllowMethods, ", ")
+	headers := strings.Join(c.AllowHeaders, ", ")
+	expose := strings.Join(c.ExposeHeaders, ", ")
+
+	return func(next context.Handler) context.Handler {
+		return func(ctx *context.Ctx) error {
+			origin := ctx.Get("Origin")
+			if origin == "" {
+				return next(ctx)
+			}
+
+			allowed, wildcard := allowOrigin(origin, c.AllowOrigins)
+			if !allowed {
+				if ctx.Method() == "OPTIONS" && ctx.Get("Access-Control-Request-Method") != "" {
+					return ctx.Forbidden("origin not allowed")
+				}
+				return next(ctx)
+			}
+
+			if wildcard {
+				ctx.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				ctx.Set("Access-Control-Allow-Origin", origin)
+				ctx.Response.Header.Add("Vary", "Origin")
+			}
+			if c.AllowCredentials {
+				ctx.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if ctx.Method() == "OPTIONS" && ctx.Get("Access-Control-Request-Method") != "" {
+				if allowed, ok := context.AllowedMethodsKey.Get(ctx); ok && routeMethods {
+					ctx.Set("Access-Control-Allow-Methods", strings.Join(allowed, ", "))
+				} else {
+					ctx.Set("Access-Control-Allow-Methods", methods)
+				}
+				if headers != "" {
+					ctx.Set("Access-Control-Allow-Headers", headers)
+				} else if requested := ctx.Get("Access-Control-Request-Headers"); requested != "" {
+					ctx.Set("Access-Control-Allow-Headers", requested)
+				}
+				if c.MaxAge > 0 {
+					ctx.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge.Seconds())))
+				}
+				ctx.Response.SetStatusCode(constant.StatusNoContent)
+				return nil
+			}
+
+			if expose != "" {
+				ctx.Set("Access-Control-Expose-Headers", expose)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func allowOrigin(origin string, allowed []string) (bool, bool) {
+	for _, o := range allowed {
+		if o == "*" {
+			return true, true
+		}
+		if strings.EqualFold(o, origin) {
+			return true, false
+		}
+	}
+	return false, false
+}
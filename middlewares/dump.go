@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"fastrest/context"
+)
+
+type DumpConfig struct {
+	Output      io.Writer
+	Redactor    *Redactor
+	MaxBodySize int
+	SkipPaths   []string
+	NoBody      bool
+}
+
+func Dump(cfg *DumpConfig) context.Middleware {
+	c := DumpConfig{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+	if c.Redactor == nil {
+		c.Redactor = DefaultRedactor()
+	}
+	if c.MaxBodySize <= 0 {
+		c.MaxBodySize = 4 << 10
+	}
+
+	var mu sync.Mutex
+	return func(next context.Handler) context.Handler {
+		return func(ctx *context.Ctx) error {
+			path := ctx.Path()
+			if skipPath(path, c.SkipPaths) {
+				return next(ctx)
+			}
+
+			var buf bytes.Buffer
+			target := path
+			if q := c.Redactor.Query(string(ctx.QueryArgs().QueryString())); q != "" {
+				target += "?" + q
+			}
+			fmt.Fprintf(&buf, "--> %s %s\n", ctx.Method(), target)
+			for k, v := range ctx.Request.Header.All() {
+				fmt.Fprintf(&buf, "%s: %s\n", k, c.Redactor.Header(string(k), string(v)))
+			}
+			if !c.NoBody {
+				writeDumpBody(&buf, c.Redactor.Body(string(ctx.Request.Header.ContentType()), ctx.Request.Body()), c.MaxBodySize)
+			}
+
+			start := ctx.Now()
+			err := next(ctx)
+			latency := ctx.Clock().Since(start)
+
+			status := context.ErrorStatus(ctx, err)
+			fmt.Fprintf(&buf, "<-- %d %s (%s)\n", status, path, latency.Round(time.Microsecond))
+			for k, v := range ctx.Response.Header.All() {
+				fmt.Fprintf(&buf, "%s: %s\n", k, c.Redactor.Header(string(k), string(v)))
+			}
+			if !c.NoBody {
+				writeDumpBody(&buf, c.Redactor.Body(string(ctx.Response.Header.ContentType()), ctx.Response.Body()), c.MaxBodySize)
+			}
+			if err != nil {
+				fmt.Fprintf(&buf, "error: %s\n", err.Error())
+			}
+			buf.WriteByte('\n')
+
+			mu.Lock()
+			c.Output.Write(buf.Bytes())
+			mu.Unlock()
+
+			return err
+		}
+	}
+}
+
+func writeDumpBody(buf *bytes.Buffer, body []byte, max int) {
+	if len(body) == 0 {
+		return
+	}
+	buf.WriteByte('\n')
+	if len(body) > max {
+		buf.Write(body[:max])
+		fmt.Fprintf(buf, "... (%d bytes truncated)\n", len(body)-max)
+		return
+	}
+	buf.Write(body)
+	if body[len(body)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+}
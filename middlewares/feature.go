@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"strings"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+type FeatureOption func(*featureConfig)
+
+type featureConfig struct {
+	status int
+}
+
+func WithFeatureStatus(status int) FeatureOption {
+	return func(cfg *featureConfig) {
+		cfg.status = status
+	}
+}
+
+func RequireFeature(name string, opts ...FeatureOption) context.Middleware {
+	cfg := &featureConfig{status: constant.StatusNotFound}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			if !c.FeatureEnabled(name) {
+				return c.SendError(cfg.status, strings.ToLower(constant.StatusText(cfg.status)))
+			}
+			return next(c)
+		}
+	}
+}
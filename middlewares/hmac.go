@@ -0,0 +1,125 @@
+package middlewares
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"fastrest/context"
+	"fastrest/crypto"
+)
+
+type HMACKeyFunc func(keyID string) ([]byte, bool)
+
+type NonceStore interface {
+	Use(nonce string, expires time.Time) bool
+}
+
+type HMACConfig struct {
+	Keys      HMACKeyFunc
+	Headers   crypto.SignatureHeaders
+	ClockSkew time.Duration
+	Nonces    NonceStore
+}
+
+func HMACKeys(keys map[string][]byte) HMACKeyFunc {
+	return func(keyID string) ([]byte, bool) {
+		secret, ok := keys[keyID]
+		return secret, ok
+	}
+}
+
+func HMACAuth(cfg *HMACConfig) context.Middleware {
+	c := HMACConfig{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Keys == nil {
+		c.Keys = func(string) ([]byte, bool) { return nil, false }
+	}
+	if c.ClockSkew <= 0 {
+		c.ClockSkew = 5 * time.Minute
+	}
+	if c.Nonces == nil {
+		c.Nonces = NewMemoryNonceStore()
+	}
+	headers := c.Headers.WithDefaults()
+
+	return func(next context.Handler) context.Handler {
+		return func(ctx *context.Ctx) error {
+			signature := ctx.Get(headers.Signature)
+			keyID := ctx.Get(headers.KeyID)
+			nonce := ctx.Get(headers.Nonce)
+			if signature == "" || keyID == "" || nonce == "" {
+				return ctx.Unauthorized("missing request signature")
+			}
+
+			timestamp, err := strconv.ParseInt(ctx.Get(headers.Timestamp), 10, 64)
+			if err != nil {
+				return ctx.Unauthorized("invalid signature timestamp")
+			}
+			now := ctx.Now()
+			if math.Abs(now.Sub(time.Unix(timestamp, 0)).Seconds()) > c.ClockSkew.Seconds() {
+				return ctx.Unauthorized("signature expired")
+			}
+
+			secret, ok := c.Keys(keyID)
+			if !ok {
+				return ctx.Unauthorized("unknown signing key")
+			}
+			canonical := crypto.CanonicalRequest(ctx.Method(), string(ctx.RequestURI()), timestamp, nonce, ctx.Request.Body())
+			if !crypto.VerifySignature(secret, canonical, signature) {
+				return ctx.Unauthorized("invalid request signature")
+			}
+
+			if !c.Nonces.Use(keyID+":"+nonce, time.Unix(timestamp, 0).Add(c.ClockSkew)) {
+				return ctx.Unauthorized("replayed request")
+			}
+
+			ctx.SetAuth(&context.AuthInfo{
+				Type:     "hmac",
+				Value:    keyID,
+				Subject:  keyID,
+				Username: keyID,
+				Valid:    true,
+			})
+			return next(ctx)
+		}
+	}
+}
+
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	sweep  time.Time
+	now    func() time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		nonces: make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+func (s *MemoryNonceStore) Use(nonce string, expires time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.After(s.sweep) {
+		for n, exp := range s.nonces {
+			if now.After(exp) {
+				delete(s.nonces, n)
+			}
+		}
+		s.sweep = now.Add(time.Minute)
+	}
+
+	if exp, ok := s.nonces[nonce]; ok && !now.After(exp) {
+		return false
+	}
+	s.nonces[nonce] = expires
+	return true
+}
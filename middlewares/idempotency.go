@@ -0,0 +1,222 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+	"fastrest/pkg/clock"
+)
+
+const (
+	IdempotencyHeader = "Idempotency-Key"
+	ReplayedHeader    = "Idempotent-Replayed"
+)
+
+type IdempotencyRecord struct {
+	Fingerprint string
+	Response    *CachedResponse
+}
+
+type IdempotencyStore interface {
+	Get(key string) (*IdempotencyRecord, bool)
+	Set(key string, record *IdempotencyRecord, ttl time.Duration)
+	Lock(key string) bool
+	Unlock(key string)
+}
+
+type IdempotencyOption func(*idempotencyConfig)
+
+type idempotencyConfig struct {
+	store IdempotencyStore
+	scope func(c *context.Ctx) string
+}
+
+func WithIdempotencyStore(store IdempotencyStore) IdempotencyOption {
+	return func(cfg *idempotencyConfig) {
+		cfg.store = store
+	}
+}
+
+func WithIdempotencyScope(scope func(c *context.Ctx) string) IdempotencyOption {
+	return func(cfg *idempotencyConfig) {
+		cfg.scope = scope
+	}
+}
+
+func Idempotency(ttl time.Duration, opts ...IdempotencyOption) context.Middleware {
+	cfg := &idempotencyConfig{scope: defaultIdempotencyScope}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.store == nil {
+		cfg.store = NewMemoryIdempotencyStore()
+	}
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			method := c.Method()
+			header := c.Get(IdempotencyHeader)
+			if header == "" || method != "POST" && method != "PATCH" {
+				return next(c)
+			}
+
+			key := header
+			if cfg.scope != nil {
+				key = cfg.scope(c) + ":" + header
+			}
+			fingerprint := requestFingerprint(c)
+
+			if handled := replay(c, cfg.store, key, fingerprint); handled {
+				return nil
+			}
+			if !cfg.store.Lock(key) {
+				return c.SendError(constant.StatusConflict, "a request with this idempotency key is already in progress")
+			}
+			defer cfg.store.Unlock(key)
+
+			if handled := replay(c, cfg.store, key, fingerprint); handled {
+				return nil
+			}
+
+			if err := next(c); err != nil {
+				return err
+			}
+			if c.Response.StatusCode() < 500 {
+				cfg.store.Set(key, &IdempotencyRecord{Fingerprint: fingerprint, Response: captureResponse(c)}, ttl)
+			}
+			return nil
+		}
+	}
+}
+
+func defaultIdempotencyScope(c *context.Ctx) string {
+	if auth := c.GetAuth(); auth != nil && auth.Valid {
+		switch {
+		case auth.Subject != "":
+			return auth.Type + ":" + auth.Subject
+		case auth.Username != "":
+			return auth.Type + ":" + auth.Username
+		case auth.Value != "":
+			return auth.Type + ":" + credentialDigest(auth.Value)
+		}
+	}
+	credential := c.Get("Authorization")
+	if credential == "" {
+		credential = c.Get("X-API-Key")
+	}
+	if credential == "" {
+		return ""
+	}
+	return "credential:" + credentialDigest(credential)
+}
+
+func credentialDigest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:16])
+}
+
+func replay(c *context.Ctx, store IdempotencyStore, key, fingerprint string) bool {
+	record, ok := store.Get(key)
+	if !ok {
+		return false
+	}
+	if record.Fingerprint != fingerprint {
+		c.SendError(constant.StatusUnprocessableEntity, "idempotency key reused with a different request")
+		return true
+	}
+	writeStored(c, record.Response)
+	c.Set(ReplayedHeader, "true")
+	return true
+}
+
+func requestFingerprint(c *context.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method()))
+	h.Write([]byte{0})
+	h.Write(c.Request.URI().RequestURI())
+	h.Write([]byte{0})
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type MemoryIdempotencyStore struct {
+	mu       sync.Mutex
+	records  map[string]*idempotencyEntry
+	inFlight map[string]bool
+	clock    clock.Clock
+	swept    time.Time
+}
+
+type idempotencyEntry struct {
+	record  *IdempotencyRecord
+	expires time.Time
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		records:  make(map[string]*idempotencyEntry),
+		inFlight: make(map[string]bool),
+		clock:    clock.System,
+	}
+}
+
+func (s *MemoryIdempotencyStore) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+func (s *MemoryIdempotencyStore) Get(key string) (*IdempotencyRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.records[key]
+	if !ok {
+		return nil, false
+	}
+	if s.clock.Now().After(entry.expires) {
+		delete(s.records, key)
+		return nil, false
+	}
+	return entry.record, true
+}
+
+func (s *MemoryIdempotencyStore) Set(key string, record *IdempotencyRecord, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if now.Sub(s.swept) > time.Minute {
+		for k, entry := range s.records {
+			if now.After(entry.expires) {
+				delete(s.records, k)
+			}
+		}
+		s.swept = now
+	}
+	s.records[key] = &idempotencyEntry{record: record, expires: now.Add(ttl)}
+}
+
+func (s *MemoryIdempotencyStore) Lock(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inFlight[key] {
+		return false
+	}
+	s.inFlight[key] = true
+	return true
+}
+
+func (s *MemoryIdempotencyStore) Unlock(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, key)
+}
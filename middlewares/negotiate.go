@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"strings"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+func RequireContentType(types ...string) context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			if hasBody(c) && !c.Is(types...) {
+				return c.SendError(constant.StatusUnsupportedMediaType, "unsupported content type, expected "+strings.Join(types, ", "))
+			}
+			return next(c)
+		}
+	}
+}
+
+func RequireAccept(types ...string) context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			if c.Accepts(types...) == "" {
+				return c.SendError(constant.StatusNotAcceptable, "not acceptable, available: "+strings.Join(types, ", "))
+			}
+			return next(c)
+		}
+	}
+}
+
+func hasBody(c *context.Ctx) bool {
+	switch c.Method() {
+	case "POST", "PUT", "PATCH":
+		return true
+	}
+	return len(c.Body()) > 0 || c.Request.Header.ContentLength() > 0
+}
@@ -0,0 +1,171 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+const DefaultRedactMask = "[REDACTED]"
+
+var DefaultRedactHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie",
+	"X-API-Key", "X-Auth-Token", "X-CSRF-Token",
+}
+
+var DefaultRedactFields = []string{
+	"password", "passwd", "secret", "client_secret", "token", "access_token",
+	"refresh_token", "id_token", "api_key", "apikey", "ssn", "credit_card", "card_number", "cvv",
+}
+
+type RedactConfig struct {
+	Headers    []string
+	Fields     []string
+	Mask       string
+	NoDefaults bool
+}
+
+type Redactor struct {
+	headers map[string]struct{}
+	fields  map[string]struct{}
+	mask    string
+}
+
+var defaultRedactor = NewRedactor(nil)
+
+func DefaultRedactor() *Redactor {
+	return defaultRedactor
+}
+
+func NewRedactor(cfg *RedactConfig) *Redactor {
+	c := RedactConfig{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Mask == "" {
+		c.Mask = DefaultRedactMask
+	}
+
+	r := &Redactor{
+		headers: make(map[string]struct{}),
+		fields:  make(map[string]struct{}),
+		mask:    c.Mask,
+	}
+	headers, fields := c.Headers, c.Fields
+	if !c.NoDefaults {
+		headers = append(append([]string{}, DefaultRedactHeaders...), headers...)
+		fields = append(append([]string{}, DefaultRedactFields...), fields...)
+	}
+	for _, h := range headers {
+		r.headers[strings.ToLower(h)] = struct{}{}
+	}
+	for _, f := range fields {
+		r.fields[strings.ToLower(f)] = struct{}{}
+	}
+	return r
+}
+
+func (r *Redactor) SensitiveHeader(name string) bool {
+	_, ok := r.headers[strings.ToLower(name)]
+	return ok
+}
+
+func (r *Redactor) SensitiveField(name string) bool {
+	_, ok := r.fields[strings.ToLower(name)]
+	return ok
+}
+
+func (r *Redactor) Header(name, value string) string {
+	if value == "" || !r.SensitiveHeader(name) {
+		return value
+	}
+	if scheme, _, ok := strings.Cut(value, " "); ok && strings.EqualFold(name, "Authorization") {
+		return scheme + " " + r.mask
+	}
+	return r.mask
+}
+
+func (r *Redactor) Query(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return r.mask
+	}
+	changed := false
+	for key, vals := range values {
+		if r.SensitiveField(key) {
+			for i := range vals {
+				vals[i] = r.mask
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return raw
+	}
+	return values.Encode()
+}
+
+func (r *Redactor) JSON(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return []byte(r.mask)
+	}
+	if !r.redactValue(v) {
+		return body
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return []byte(r.mask)
+	}
+	return out
+}
+
+func (r *Redactor) Body(contentType string, body []byte) []byte {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	switch mediaType = strings.TrimSpace(mediaType); {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return r.JSON(body)
+	case mediaType == "application/x-www-form-urlencoded":
+		return []byte(r.Query(string(body)))
+	case strings.HasPrefix(mediaType, "multipart/"):
+		if len(body) == 0 {
+			return body
+		}
+		return []byte(r.mask)
+	default:
+		return body
+	}
+}
+
+func (r *Redactor) redactValue(v interface{}) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if r.SensitiveField(k) {
+				val[k] = r.mask
+				changed = true
+				continue
+			}
+			if r.redactValue(child) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if r.redactValue(child) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
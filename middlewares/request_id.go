@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"fastrest/context"
+	"fastrest/pkg/logging"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+func RequestID() context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			id := c.Get(RequestIDHeader)
+			if id == "" || len(id) > maxRequestIDLength {
+				id = newRequestID()
+			}
+
+			c.SetRequestID(id)
+			c.Set(RequestIDHeader, id)
+			c.Logger = logging.With(c.Logger,
+				"request_id", id,
+				"method", c.Method(),
+				"path", c.Path())
+
+			return next(c)
+		}
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
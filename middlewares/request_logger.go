@@ -2,34 +2,64 @@ package middlewares
 
 import (
 	"fmt"
-	"time"
+	"io"
+	"os"
 
 	"fastrest/constant"
 	"fastrest/context"
 )
 
+type RequestLoggerConfig struct {
+	Output   io.Writer
+	Query    bool
+	Headers  []string
+	Redactor *Redactor
+}
+
 func RequestLogger() context.Middleware {
+	return RequestLoggerWithConfig(nil)
+}
+
+func RequestLoggerWithConfig(cfg *RequestLoggerConfig) context.Middleware {
+	rc := RequestLoggerConfig{}
+	if cfg != nil {
+		rc = *cfg
+	}
+	if rc.Output == nil {
+		rc.Output = os.Stdout
+	}
+	if rc.Redactor == nil {
+		rc.Redactor = DefaultRedactor()
+	}
+
 	return func(next context.Handler) context.Handler {
 		return func(c *context.Ctx) error {
-			start := time.Now()
+			start := c.Now()
 
 			err := next(c)
 
-			duration := time.Since(start)
-			status := c.Response.StatusCode()
-			if status == 0 {
-				status = 200
-			}
+			duration := c.Clock().Since(start)
+			status := context.ErrorStatus(c, err)
 
 			method := c.Method()
 			path := c.Path()
+			if rc.Query {
+				if q := rc.Redactor.Query(string(c.QueryArgs().QueryString())); q != "" {
+					path += "?" + q
+				}
+			}
+			for _, h := range rc.Headers {
+				if v := c.Get(h); v != "" {
+					path += fmt.Sprintf(" %s=%q", h, rc.Redactor.Header(h, v))
+				}
+			}
 			ip := c.IP()
 
-			now := time.Now().Format("15:04:05")
+			now := c.Now().Format("15:04:05")
 			statusColor := getStatusColor(status)
 			methodColor := getMethodColor(method)
 
-			fmt.Printf("%s%s%s | %sREQ%s | %s%-7s%s | %s%3d%s | %12v | %s | %s\n",
+			fmt.Fprintf(rc.Output, "%s%s%s | %sREQ%s | %s%-7s%s | %s%3d%s | %12v | %s | %s\n",
 				constant.ColorGray, now, constant.ColorReset,
 				constant.ColorWhite, constant.ColorReset,
 				methodColor, method, constant.ColorReset,
@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"net/http"
+	"sort"
+
+	"fastrest/context"
+)
+
+var DefaultSecureHeaders = map[string]string{
+	"X-Content-Type-Options":    "nosniff",
+	"X-Frame-Options":           "SAMEORIGIN",
+	"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+	"Referrer-Policy":           "strict-origin-when-cross-origin",
+	"Content-Security-Policy":   "default-src 'self'; base-uri 'self'; frame-ancestors 'self'; object-src 'none'",
+	"Permissions-Policy":        "camera=(), microphone=(), geolocation=(), payment=()",
+}
+
+type SecureOption func(map[string]string)
+
+func SecureHeader(name, value string) SecureOption {
+	return func(headers map[string]string) {
+		name = http.CanonicalHeaderKey(name)
+		if value == "" {
+			delete(headers, name)
+			return
+		}
+		headers[name] = value
+	}
+}
+
+func Secure(opts ...SecureOption) context.Middleware {
+	headers := make(map[string]string, len(DefaultSecureHeaders))
+	for k, v := range DefaultSecureHeaders {
+		headers[k] = v
+	}
+	for _, opt := range opts {
+		opt(headers)
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			for _, name := range names {
+				c.Set(name, headers[name])
+			}
+			return next(c)
+		}
+	}
+}
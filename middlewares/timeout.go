@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	stdctx "context"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+type timeoutResult struct {
+	err   error
+	panic interface{}
+}
+
+func Timeout(d time.Duration) context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			if d <= 0 {
+				return next(c)
+			}
+
+			ctx, cancel := stdctx.WithTimeout(c.Context(), d)
+			defer cancel()
+
+			detached := c.Detach()
+			detached.SetContext(ctx)
+
+			done := make(chan timeoutResult, 1)
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						done <- timeoutResult{panic: r}
+					}
+				}()
+				done <- timeoutResult{err: next(detached)}
+			}()
+
+			select {
+			case res := <-done:
+				if res.panic != nil {
+					panic(res.panic)
+				}
+				c.Adopt(detached)
+				return res.err
+			case <-ctx.Done():
+				c.SetTimedOut(true)
+				return c.SendError(constant.StatusServiceUnavailable, "request timed out")
+			}
+		}
+	}
+}
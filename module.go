@@ -0,0 +1,85 @@
+package fastrest
+
+import (
+	stdctx "context"
+	"fmt"
+	"sync"
+)
+
+type Module interface {
+	Name() string
+	Routes(r *Router)
+	Middlewares() []Middleware
+	OnStart(ctx stdctx.Context) error
+	OnStop(ctx stdctx.Context) error
+}
+
+type BaseModule struct{}
+
+func (BaseModule) Routes(r *Router) {}
+
+func (BaseModule) Middlewares() []Middleware { return nil }
+
+func (BaseModule) OnStart(ctx stdctx.Context) error { return nil }
+
+func (BaseModule) OnStop(ctx stdctx.Context) error { return nil }
+
+type modules struct {
+	mu      sync.Mutex
+	list    []Module
+	started []Module
+}
+
+func (a *App) Register(mods ...Module) {
+	a.modules.mu.Lock()
+	defer a.modules.mu.Unlock()
+
+	for _, m := range mods {
+		for _, existing := range a.modules.list {
+			if existing.Name() == m.Name() {
+				panic(fmt.Sprintf("fastrest: module %q registered twice", m.Name()))
+			}
+		}
+		r := a.Group("")
+		r.Use(m.Middlewares()...)
+		m.Routes(r)
+		a.modules.list = append(a.modules.list, m)
+	}
+}
+
+func (a *App) Modules() []string {
+	a.modules.mu.Lock()
+	defer a.modules.mu.Unlock()
+
+	names := make([]string, len(a.modules.list))
+	for i, m := range a.modules.list {
+		names[i] = m.Name()
+	}
+	return names
+}
+
+func (a *App) startModules(ctx stdctx.Context) error {
+	a.modules.mu.Lock()
+	defer a.modules.mu.Unlock()
+
+	for _, m := range a.modules.list {
+		if err := m.OnStart(ctx); err != nil {
+			return fmt.Errorf("fastrest: start module %q: %w", m.Name(), err)
+		}
+		a.modules.started = append(a.modules.started, m)
+	}
+	return nil
+}
+
+func (a *App) stopModules(ctx stdctx.Context) {
+	a.modules.mu.Lock()
+	started := a.modules.started
+	a.modules.started = nil
+	a.modules.mu.Unlock()
+
+	for i := len(started) - 1; i >= 0; i-- {
+		if err := started[i].OnStop(ctx); err != nil {
+			a.logger.Warn("module failed to stop", "module", started[i].Name(), "error", err.Error())
+		}
+	}
+}
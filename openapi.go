@@ -0,0 +1,128 @@
+package fastrest
+
+import (
+	"strings"
+	"time"
+
+	"fastrest/middlewares"
+	"fastrest/openapi"
+)
+
+func (a *App) OpenAPI(title, version string) *openapi.Document {
+	doc := &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: title, Version: version},
+		Paths:   make(map[string]*openapi.PathItem),
+	}
+
+	a.router.mu.RLock()
+	routes := append([]*Route{}, *a.router.routes...)
+	a.router.mu.RUnlock()
+
+	for _, rt := range routes {
+		template, params := openAPIPath(rt.Path)
+		item := doc.Paths[template]
+		if item == nil {
+			item = &openapi.PathItem{}
+			doc.Paths[template] = item
+		}
+
+		op := &openapi.Operation{
+			OperationID: rt.Name,
+			Parameters:  params,
+			Responses:   map[string]*openapi.Response{"default": {Description: "Default response"}},
+			Extensions:  make(map[string]interface{}),
+		}
+		if rt.Host != "" {
+			op.Extensions["x-fastrest-host"] = rt.Host
+		}
+		op.Summary = rt.meta.summary
+		op.Description = rt.meta.description
+		op.Tags = rt.meta.tags
+		op.Deprecated = rt.meta.deprecated
+		if !rt.meta.sunset.IsZero() {
+			op.Extensions["x-sunset"] = rt.meta.sunset.UTC().Format(time.RFC3339)
+		}
+		if rt.policy != nil {
+			applyPolicy(doc, op, rt.policy)
+		}
+		item.SetOperation(rt.Method, op)
+	}
+	return doc
+}
+
+func openAPIPath(path string) (string, []*openapi.Parameter) {
+	parts := strings.Split(path, "/")
+	var params []*openapi.Parameter
+	for i, part := range parts {
+		name := ""
+		switch {
+		case strings.HasPrefix(part, ":"):
+			name = part[1:]
+		case part == "*" && i == len(parts)-1:
+			name = "wildcard"
+		default:
+			continue
+		}
+		parts[i] = "{" + name + "}"
+		params = append(params, &openapi.Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &openapi.Schema{Type: "string"},
+		})
+	}
+	return strings.Join(parts, "/"), params
+}
+
+func applyPolicy(doc *openapi.Document, op *openapi.Operation, p *Policy) {
+	if p.Auth != nil {
+		if p.Auth.BasicValidator != nil {
+			addSecurity(doc, op, "basicAuth", &openapi.SecurityScheme{Type: "http", Scheme: "basic"})
+		}
+		if p.Auth.BearerValidator != nil {
+			addSecurity(doc, op, "bearerAuth", &openapi.SecurityScheme{Type: "http", Scheme: "bearer"})
+		}
+		if p.Auth.APIKeyValidator != nil {
+			addSecurity(doc, op, "apiKeyAuth", &openapi.SecurityScheme{Type: "apiKey", Name: p.Auth.APIKeyName, In: "header"})
+		}
+	}
+	if len(p.Scopes) > 0 {
+		op.Extensions["x-fastrest-scopes"] = p.Scopes
+	}
+	if p.CORS != nil {
+		origins, methods := p.CORS.AllowOrigins, p.CORS.AllowMethods
+		if len(origins) == 0 {
+			origins = []string{"*"}
+		}
+		if len(methods) == 0 {
+			methods = middlewares.DefaultCORSMethods
+		}
+		cors := map[string]interface{}{
+			"allowOrigins":     origins,
+			"allowMethods":     methods,
+			"allowCredentials": p.CORS.AllowCredentials,
+		}
+		if len(p.CORS.AllowHeaders) > 0 {
+			cors["allowHeaders"] = p.CORS.AllowHeaders
+		}
+		if len(p.CORS.ExposeHeaders) > 0 {
+			cors["exposeHeaders"] = p.CORS.ExposeHeaders
+		}
+		if p.CORS.MaxAge > 0 {
+			cors["maxAge"] = int(p.CORS.MaxAge.Seconds())
+		}
+		op.Extensions["x-fastrest-cors"] = cors
+	}
+	if p.Cache != nil {
+		op.Extensions["x-fastrest-cache"] = map[string]interface{}{"ttl": p.Cache.TTL.String()}
+	}
+}
+
+func addSecurity(doc *openapi.Document, op *openapi.Operation, name string, scheme *openapi.SecurityScheme) {
+	if doc.Components.SecuritySchemes == nil {
+		doc.Components.SecuritySchemes = make(map[string]*openapi.SecurityScheme)
+	}
+	doc.Components.SecuritySchemes[name] = scheme
+	op.Security = append(op.Security, map[string][]string{name: {}})
+}
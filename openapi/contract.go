@@ -0,0 +1,316 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Mismatch struct {
+	Method   string
+	Path     string
+	Location string
+	Message  string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s %s: %s: %s", m.Method, m.Path, m.Location, m.Message)
+}
+
+type Request struct {
+	Method      string
+	Path        string
+	Query       map[string]string
+	Headers     map[string]string
+	ContentType string
+	Body        []byte
+}
+
+type ResponseData struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+func (d *Document) ValidateRequest(req *Request) []Mismatch {
+	var result []Mismatch
+	report := func(location, format string, args ...interface{}) {
+		result = append(result, Mismatch{
+			Method:   req.Method,
+			Path:     req.Path,
+			Location: location,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	_, item, op := d.FindOperation(req.Method, req.Path)
+	if item == nil {
+		report("path", "path is not documented")
+		return result
+	}
+	if op == nil {
+		report("method", "method is not documented for this path")
+		return result
+	}
+
+	params := append(append([]*Parameter{}, item.Parameters...), op.Parameters...)
+	for _, p := range params {
+		var (
+			value string
+			found bool
+		)
+		switch p.In {
+		case "query":
+			value, found = req.Query[p.Name]
+		case "header":
+			value, found = lookupHeader(req.Headers, p.Name)
+		default:
+			continue
+		}
+		location := p.In + "." + p.Name
+		if !found || value == "" {
+			if p.Required {
+				report(location, "required parameter is missing")
+			}
+			continue
+		}
+		if msg := checkScalar(d.resolve(p.Schema), value); msg != "" {
+			report(location, "%s", msg)
+		}
+	}
+
+	if op.RequestBody == nil {
+		return result
+	}
+	if len(req.Body) == 0 {
+		if op.RequestBody.Required {
+			report("body", "request body is required")
+		}
+		return result
+	}
+
+	media, ok := findMedia(op.RequestBody.Content, req.ContentType)
+	if !ok {
+		report("body", "content type %q is not documented", req.ContentType)
+		return result
+	}
+	for _, msg := range d.validateBody(media, req.ContentType, req.Body) {
+		report("body", "%s", msg)
+	}
+	return result
+}
+
+func (d *Document) ValidateResponse(method, path string, resp *ResponseData) []Mismatch {
+	var result []Mismatch
+	report := func(location, format string, args ...interface{}) {
+		result = append(result, Mismatch{
+			Method:   method,
+			Path:     path,
+			Location: location,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	_, item, op := d.FindOperation(method, path)
+	if item == nil || op == nil {
+		report("operation", "operation is not documented")
+		return result
+	}
+
+	spec := findResponse(op.Responses, resp.Status)
+	if spec == nil {
+		report("status", "status %d is not documented", resp.Status)
+		return result
+	}
+	if len(spec.Content) == 0 || len(resp.Body) == 0 {
+		return result
+	}
+
+	media, ok := findMedia(spec.Content, resp.ContentType)
+	if !ok {
+		report("response.body", "content type %q is not documented for status %d", resp.ContentType, resp.Status)
+		return result
+	}
+	for _, msg := range d.validateBody(media, resp.ContentType, resp.Body) {
+		report("response.body", "%s", msg)
+	}
+	return result
+}
+
+func (d *Document) validateBody(media *MediaType, contentType string, body []byte) []string {
+	if media == nil || media.Schema == nil || !isJSON(contentType) {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{"invalid JSON: " + err.Error()}
+	}
+	var msgs []string
+	d.validateValue(d.resolve(media.Schema), value, "$", &msgs)
+	return msgs
+}
+
+func (d *Document) validateValue(s *Schema, value interface{}, at string, msgs *[]string) {
+	if s == nil {
+		return
+	}
+	if value == nil {
+		if !s.Nullable && s.Type != "" {
+			*msgs = append(*msgs, fmt.Sprintf("%s: expected %s, got null", at, s.Type))
+		}
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		*msgs = append(*msgs, fmt.Sprintf("%s: value %v is not one of the allowed values", at, value))
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			*msgs = append(*msgs, fmt.Sprintf("%s: expected object, got %s", at, typeName(value)))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				*msgs = append(*msgs, fmt.Sprintf("%s: missing required property %q", at, name))
+			}
+		}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if v, ok := obj[name]; ok {
+				d.validateValue(d.resolve(s.Properties[name]), v, at+"."+name, msgs)
+			}
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			*msgs = append(*msgs, fmt.Sprintf("%s: expected array, got %s", at, typeName(value)))
+			return
+		}
+		for i, v := range arr {
+			d.validateValue(d.resolve(s.Items), v, fmt.Sprintf("%s[%d]", at, i), msgs)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			*msgs = append(*msgs, fmt.Sprintf("%s: expected string, got %s", at, typeName(value)))
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			*msgs = append(*msgs, fmt.Sprintf("%s: expected integer, got %s", at, typeName(value)))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			*msgs = append(*msgs, fmt.Sprintf("%s: expected number, got %s", at, typeName(value)))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			*msgs = append(*msgs, fmt.Sprintf("%s: expected boolean, got %s", at, typeName(value)))
+		}
+	}
+}
+
+func checkScalar(s *Schema, value string) string {
+	if s == nil {
+		return ""
+	}
+	switch s.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Sprintf("expected integer, got %q", value)
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Sprintf("expected number, got %q", value)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Sprintf("expected boolean, got %q", value)
+		}
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		return fmt.Sprintf("value %q is not one of the allowed values", value)
+	}
+	return ""
+}
+
+func findResponse(responses map[string]*Response, status int) *Response {
+	if r, ok := responses[strconv.Itoa(status)]; ok {
+		return r
+	}
+	if r, ok := responses[fmt.Sprintf("%dXX", status/100)]; ok {
+		return r
+	}
+	return responses["default"]
+}
+
+func findMedia(content map[string]*MediaType, contentType string) (*MediaType, bool) {
+	if len(content) == 0 {
+		return nil, true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	}
+	if m, ok := content[mediaType]; ok {
+		return m, true
+	}
+	if slash := strings.Index(mediaType, "/"); slash > 0 {
+		if m, ok := content[mediaType[:slash]+"/*"]; ok {
+			return m, true
+		}
+	}
+	m, ok := content["*/*"]
+	return m, ok
+}
+
+func lookupHeader(headers map[string]string, name string) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func isJSON(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,217 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type PathItem struct {
+	Parameters []*Parameter `json:"parameters,omitempty"`
+	Get        *Operation   `json:"get,omitempty"`
+	Put        *Operation   `json:"put,omitempty"`
+	Post       *Operation   `json:"post,omitempty"`
+	Delete     *Operation   `json:"delete,omitempty"`
+	Options    *Operation   `json:"options,omitempty"`
+	Head       *Operation   `json:"head,omitempty"`
+	Patch      *Operation   `json:"patch,omitempty"`
+}
+
+type Operation struct {
+	OperationID string                 `json:"operationId,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Parameters  []*Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+	Security    []map[string][]string  `json:"security,omitempty"`
+	Extensions  map[string]interface{} `json:"-"`
+}
+
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type plain Operation
+	data, err := json.Marshal(plain(o))
+	if err != nil || len(o.Extensions) == 0 {
+		return data, err
+	}
+
+	ext := make(map[string]interface{}, len(o.Extensions))
+	for k, v := range o.Extensions {
+		if !strings.HasPrefix(k, "x-") {
+			k = "x-" + k
+		}
+		ext[k] = v
+	}
+	extData, err := json.Marshal(ext)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(data[:len(data)-1], ','), extData[1:]...), nil
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Schema struct {
+	Ref        string             `json:"$ref,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Nullable   bool               `json:"nullable,omitempty"`
+	Enum       []interface{}      `json:"enum,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	Name   string `json:"name,omitempty"`
+	In     string `json:"in,omitempty"`
+}
+
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: read spec: %w", err)
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: parse spec: %w", err)
+	}
+	if doc.Paths == nil {
+		doc.Paths = make(map[string]*PathItem)
+	}
+	return &doc, nil
+}
+
+func (p *PathItem) Operation(method string) *Operation {
+	switch strings.ToUpper(method) {
+	case "GET":
+		return p.Get
+	case "PUT":
+		return p.Put
+	case "POST":
+		return p.Post
+	case "DELETE":
+		return p.Delete
+	case "OPTIONS":
+		return p.Options
+	case "HEAD":
+		return p.Head
+	case "PATCH":
+		return p.Patch
+	default:
+		return nil
+	}
+}
+
+func (p *PathItem) SetOperation(method string, op *Operation) {
+	switch strings.ToUpper(method) {
+	case "GET":
+		p.Get = op
+	case "PUT":
+		p.Put = op
+	case "POST":
+		p.Post = op
+	case "DELETE":
+		p.Delete = op
+	case "OPTIONS":
+		p.Options = op
+	case "HEAD":
+		p.Head = op
+	case "PATCH":
+		p.Patch = op
+	}
+}
+
+func (d *Document) FindOperation(method, path string) (string, *PathItem, *Operation) {
+	var (
+		bestTemplate string
+		bestItem     *PathItem
+		bestParams   = -1
+	)
+	for template, item := range d.Paths {
+		if item == nil || !matchTemplate(template, path) {
+			continue
+		}
+		params := strings.Count(template, "{")
+		if bestParams == -1 || params < bestParams || (params == bestParams && template < bestTemplate) {
+			bestTemplate, bestItem, bestParams = template, item, params
+		}
+	}
+	if bestItem == nil {
+		return "", nil, nil
+	}
+	return bestTemplate, bestItem, bestItem.Operation(method)
+}
+
+func (d *Document) resolve(s *Schema) *Schema {
+	for depth := 0; s != nil && s.Ref != "" && depth < 32; depth++ {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		s = d.Components.Schemas[name]
+	}
+	return s
+}
+
+func matchTemplate(template, path string) bool {
+	templateParts := strings.Split(template, "/")
+	pathParts := strings.Split(path, "/")
+	if len(templateParts) != len(pathParts) {
+		return false
+	}
+	for i, part := range templateParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if pathParts[i] == "" {
+				return false
+			}
+			continue
+		}
+		if part != pathParts[i] {
+			return false
+		}
+	}
+	return true
+}
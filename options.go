@@ -0,0 +1,95 @@
+package fastrest
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+var methodOrder = map[string]int{
+	"GET": 0, "HEAD": 1, "POST": 2, "PUT": 3, "PATCH": 4, "DELETE": 5, "OPTIONS": 6,
+}
+
+func (r *Router) allowedMethods(host, path string) ([]string, string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var methods []string
+	var pattern string
+	var params []routeParam
+	for _, route := range *r.routes {
+		matched, ok := matchPath(route, path, params[:0], r.fold)
+		params = matched
+		if !ok {
+			continue
+		}
+		if route.Host != "" {
+			if _, ok := matchHost(route.Host, host, matched); !ok {
+				continue
+			}
+		}
+		if !containsString(methods, route.Method) {
+			methods = append(methods, route.Method)
+		}
+		if pattern == "" {
+			pattern = route.Path
+		}
+	}
+	return methods, pattern
+}
+
+func (a *App) serveAutoOptions(c *context.Ctx, methods []string, pattern string, start time.Time) {
+	methods = a.allowList(methods)
+	c.Set("Allow", strings.Join(methods, ", "))
+	context.AllowedMethodsKey.Set(c, methods)
+
+	status := constant.StatusNoContent
+	if err := a.routeHandler(a.options)(c); err != nil {
+		status = writeError(c, err)
+		c.Logger.Warn("handler error", "error", err.Error(), "path", c.Path(), "status", status)
+	} else if s := c.Response.StatusCode(); s != constant.StatusOK || len(c.Response.Body()) > 0 {
+		status = s
+	} else {
+		c.Response.SetStatusCode(status)
+	}
+	a.recordMetrics(c, "OPTIONS", pattern, status, a.clock.Since(start), "")
+}
+
+func (a *App) allowList(methods []string) []string {
+	if containsString(methods, "GET") && !containsString(methods, "HEAD") && !a.config.DisableAutoHead {
+		methods = append(methods, "HEAD")
+	}
+	if !containsString(methods, "OPTIONS") {
+		methods = append(methods, "OPTIONS")
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		oi, iok := methodOrder[methods[i]]
+		oj, jok := methodOrder[methods[j]]
+		switch {
+		case iok && jok:
+			return oi < oj
+		case iok != jok:
+			return iok
+		}
+		return methods[i] < methods[j]
+	})
+	return methods
+}
+
+func (a *App) registerAutoOptions() {
+	route := newRoute("OPTIONS", "", "")
+	route.Handlers = []context.Handler{func(c *context.Ctx) error { return nil }}
+	a.options = route
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
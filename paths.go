@@ -0,0 +1,144 @@
+package fastrest
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+type TrailingSlash int
+
+const (
+	TrailingSlashStrict TrailingSlash = iota
+	TrailingSlashIgnore
+	TrailingSlashRedirect
+)
+
+var (
+	errInvalidPath = errors.New("invalid request path")
+	errPathEscape  = errors.New("request path escapes the root")
+)
+
+func requestPath(raw []byte) (string, error) {
+	path := string(raw)
+	if !strings.HasPrefix(path, "/") {
+		return "", errInvalidPath
+	}
+	if canonicalPath(path) {
+		return path, nil
+	}
+
+	parts := strings.Split(path[1:], "/")
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		seg, err := url.PathUnescape(part)
+		if err != nil {
+			return "", errInvalidPath
+		}
+		for i := 0; i < len(seg); i++ {
+			if seg[i] < 0x20 || seg[i] == 0x7f {
+				return "", errInvalidPath
+			}
+		}
+
+		switch seg {
+		case ".":
+			continue
+		case "..":
+			if len(out) == 0 {
+				return "", errPathEscape
+			}
+			out = out[:len(out)-1]
+			continue
+		}
+		if strings.ContainsAny(seg, "/\\") {
+			for _, sub := range strings.FieldsFunc(seg, isPathSeparator) {
+				if sub == ".." {
+					return "", errPathEscape
+				}
+			}
+		}
+		out = append(out, escapeSegment(seg))
+	}
+
+	normalized := "/" + strings.Join(out, "/")
+	if len(out) > 0 && strings.HasSuffix(path, "/") {
+		normalized += "/"
+	}
+	return normalized, nil
+}
+
+func canonicalPath(path string) bool {
+	for i := 0; i < len(path); i++ {
+		switch b := path[i]; {
+		case b == '%' || b < 0x20 || b == 0x7f:
+			return false
+		case b == '/' && i+1 < len(path):
+			switch path[i+1] {
+			case '/':
+				return false
+			case '.':
+				rest := path[i+1:]
+				if rest == "." || rest == ".." || strings.HasPrefix(rest, "./") || strings.HasPrefix(rest, "../") {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func isPathSeparator(r rune) bool {
+	return r == '/' || r == '\\'
+}
+
+func escapeSegment(seg string) string {
+	if !strings.ContainsAny(seg, "%/") {
+		return seg
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(seg, "%", "%25"), "/", "%2F")
+}
+
+func unescapeParam(value string) string {
+	if !strings.Contains(value, "%") {
+		return value
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(value, "%2F", "/"), "%25", "%")
+}
+
+func (a *App) lookup(method, host, path string, params []routeParam) (*Route, []routeParam) {
+	route, params := a.router.find(method, host, path, params)
+	if route == nil && method == "HEAD" && !a.config.DisableAutoHead {
+		route, params = a.router.find("GET", host, path, params[:0])
+	}
+	return route, params
+}
+
+func toggleTrailingSlash(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path + "/"
+}
+
+func safeRedirectPath(path string) bool {
+	return !strings.HasPrefix(path, "//") && !strings.Contains(path, "\\")
+}
+
+func (a *App) redirectSlash(c *context.Ctx, path string) int {
+	status := constant.StatusPermanentRedirect
+	if c.IsGet() || c.IsHead() {
+		status = constant.StatusMovedPermanently
+	}
+	if query := c.URI().QueryString(); len(query) > 0 {
+		path += "?" + string(query)
+	}
+	c.Redirect(path, status)
+	return status
+}
@@ -0,0 +1,48 @@
+package logging
+
+type fieldLogger struct {
+	logger Logger
+	fields []interface{}
+}
+
+func With(logger Logger, fields ...interface{}) Logger {
+	if logger == nil || len(fields) == 0 {
+		return logger
+	}
+	if fl, ok := logger.(*fieldLogger); ok {
+		merged := make([]interface{}, 0, len(fl.fields)+len(fields))
+		merged = append(merged, fl.fields...)
+		merged = append(merged, fields...)
+		return &fieldLogger{logger: fl.logger, fields: merged}
+	}
+	return &fieldLogger{logger: logger, fields: fields}
+}
+
+func (l *fieldLogger) merge(fields []interface{}) []interface{} {
+	if len(fields) == 0 {
+		return l.fields
+	}
+	merged := make([]interface{}, 0, len(l.fields)+len(fields))
+	merged = append(merged, l.fields...)
+	return append(merged, fields...)
+}
+
+func (l *fieldLogger) Debug(msg string, fields ...interface{}) {
+	l.logger.Debug(msg, l.merge(fields)...)
+}
+
+func (l *fieldLogger) Info(msg string, fields ...interface{}) {
+	l.logger.Info(msg, l.merge(fields)...)
+}
+
+func (l *fieldLogger) Warn(msg string, fields ...interface{}) {
+	l.logger.Warn(msg, l.merge(fields)...)
+}
+
+func (l *fieldLogger) Error(msg string, fields ...interface{}) {
+	l.logger.Error(msg, l.merge(fields)...)
+}
+
+func (l *fieldLogger) Fatal(msg string, fields ...interface{}) {
+	l.logger.Fatal(msg, l.merge(fields)...)
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"fastrest/pkg/clock"
+)
+
+type JSONLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level LogLevel
+	clock clock.Clock
+}
+
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &JSONLogger{
+		w:     w,
+		level: LevelDebug,
+		clock: clock.System,
+	}
+}
+
+func (l *JSONLogger) SetLevel(level LogLevel) {
+	l.level = level
+}
+
+func (l *JSONLogger) SetOutput(writers ...io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w = combineWriters(writers)
+}
+
+func (l *JSONLogger) SetClock(c clock.Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = c
+}
+
+func (l *JSONLogger) log(level string, levelNum LogLevel, msg string, fields ...interface{}) {
+	if levelNum < l.level {
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJSONValue(&buf, l.clock.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSONValue(&buf, level)
+	buf.WriteString(`,"msg":`)
+	writeJSONValue(&buf, msg)
+
+	for i := 0; i < len(fields)-1; i += 2 {
+		key, ok := fields[i].(string)
+		if !ok || key == "time" || key == "level" || key == "msg" {
+			continue
+		}
+		buf.WriteByte(',')
+		writeJSONValue(&buf, key)
+		buf.WriteByte(':')
+		writeJSONValue(&buf, fieldValue(fields[i+1]))
+	}
+	buf.WriteString("}\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(buf.Bytes())
+}
+
+func (l *JSONLogger) Debug(msg string, fields ...interface{}) {
+	l.log("debug", LevelDebug, msg, fields...)
+}
+
+func (l *JSONLogger) Info(msg string, fields ...interface{}) {
+	l.log("info", LevelInfo, msg, fields...)
+}
+
+func (l *JSONLogger) Warn(msg string, fields ...interface{}) {
+	l.log("warn", LevelWarn, msg, fields...)
+}
+
+func (l *JSONLogger) Error(msg string, fields ...interface{}) {
+	l.log("error", LevelError, msg, fields...)
+}
+
+func (l *JSONLogger) Fatal(msg string, fields ...interface{}) {
+	l.log("fatal", LevelFatal, msg, fields...)
+	os.Exit(1)
+}
+
+func fieldValue(v interface{}) interface{} {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	return v
+}
+
+func writeJSONValue(buf *bytes.Buffer, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(err.Error())
+	}
+	buf.Write(data)
+}
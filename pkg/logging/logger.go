@@ -3,13 +3,14 @@ package logging
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
-	"time"
 
 	"fastrest/constant"
 	"fastrest/metrics"
+	"fastrest/pkg/clock"
 )
 
 type Logger interface {
@@ -23,6 +24,9 @@ type Logger interface {
 type ConsoleLogger struct {
 	mu    sync.Mutex
 	level LogLevel
+	out   io.Writer
+	color bool
+	clock clock.Clock
 }
 
 type LogLevel int
@@ -38,6 +42,9 @@ const (
 func NewLogger() *ConsoleLogger {
 	return &ConsoleLogger{
 		level: LevelDebug,
+		out:   os.Stdout,
+		color: isTerminal(os.Stdout),
+		clock: clock.System,
 	}
 }
 
@@ -45,6 +52,25 @@ func (l *ConsoleLogger) SetLevel(level LogLevel) {
 	l.level = level
 }
 
+func (l *ConsoleLogger) SetOutput(writers ...io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out = combineWriters(writers)
+	l.color = isTerminal(writers...)
+}
+
+func (l *ConsoleLogger) SetClock(c clock.Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = c
+}
+
+func (l *ConsoleLogger) SetColor(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.color = enabled
+}
+
 func (l *ConsoleLogger) log(level string, levelNum LogLevel, msg string, fields ...interface{}) {
 	if levelNum < l.level {
 		return
@@ -53,7 +79,7 @@ func (l *ConsoleLogger) log(level string, levelNum LogLevel, msg string, fields
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	now := time.Now().Format("15:04:05")
+	now := l.clock.Now().Format("15:04:05")
 	levelColor := l.getLevelColor(level)
 
 	fieldStr := ""
@@ -70,13 +96,58 @@ func (l *ConsoleLogger) log(level string, levelNum LogLevel, msg string, fields
 		}
 	}
 
-	fmt.Printf("%s%s%s | %sLOG%s | %s%-7s%s | %s%s%s%s\n",
+	if !l.color {
+		fmt.Fprintf(l.out, "%s | LOG | %-7s | %s%s\n", now, level, msg, fieldStr)
+		return
+	}
+
+	fmt.Fprintf(l.out, "%s%s%s | %sLOG%s | %s%-7s%s | %s%s%s%s\n",
 		constant.ColorGray, now, constant.ColorReset,
 		constant.ColorGray, constant.ColorReset,
 		levelColor, level, constant.ColorReset,
 		msg, constant.ColorGray, fieldStr, constant.ColorReset)
 }
 
+func isTerminal(writers ...io.Writer) bool {
+	out := make([]io.Writer, 0, len(writers))
+	for _, w := range writers {
+		if w != nil {
+			out = append(out, w)
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, os.Stdout)
+	}
+	for _, w := range out {
+		f, ok := w.(*os.File)
+		if !ok {
+			return false
+		}
+		info, err := f.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func combineWriters(writers []io.Writer) io.Writer {
+	out := make([]io.Writer, 0, len(writers))
+	for _, w := range writers {
+		if w != nil {
+			out = append(out, w)
+		}
+	}
+	switch len(out) {
+	case 0:
+		return os.Stdout
+	case 1:
+		return out[0]
+	default:
+		return io.MultiWriter(out...)
+	}
+}
+
 func (l *ConsoleLogger) getLevelColor(level string) string {
 	switch strings.ToUpper(level) {
 	case "DEBUG":
@@ -0,0 +1,164 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rotateTimeFormat = "20060102-150405.000"
+
+type RotateConfig struct {
+	Filename   string
+	MaxSize    int64
+	Interval   time.Duration
+	MaxBackups int
+	MaxAge     time.Duration
+}
+
+type RotatingFile struct {
+	mu       sync.Mutex
+	cfg      RotateConfig
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func NewRotatingFile(cfg *RotateConfig) (*RotatingFile, error) {
+	if cfg == nil || cfg.Filename == "" {
+		return nil, errors.New("logging: rotate filename is required")
+	}
+	r := &RotatingFile{cfg: *cfg}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rotate()
+}
+
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *RotatingFile) shouldRotate(n int64) bool {
+	if r.cfg.MaxSize > 0 && r.size > 0 && r.size+n > r.cfg.MaxSize {
+		return true
+	}
+	if r.cfg.Interval > 0 && time.Since(r.openedAt) >= r.cfg.Interval {
+		return true
+	}
+	return false
+}
+
+func (r *RotatingFile) open() error {
+	if dir := filepath.Dir(r.cfg.Filename); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("logging: create log dir: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(r.cfg.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("logging: open log file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("logging: stat log file: %w", err)
+	}
+
+	r.file = f
+	r.size = info.Size()
+	r.openedAt = time.Now()
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			return fmt.Errorf("logging: close log file: %w", err)
+		}
+		r.file = nil
+	}
+
+	backup := r.cfg.Filename + "." + time.Now().Format(rotateTimeFormat)
+	if err := os.Rename(r.cfg.Filename, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("logging: rename log file: %w", err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.cleanup()
+	return nil
+}
+
+func (r *RotatingFile) cleanup() {
+	if r.cfg.MaxBackups <= 0 && r.cfg.MaxAge <= 0 {
+		return
+	}
+
+	matches, err := filepath.Glob(r.cfg.Filename + ".*")
+	if err != nil {
+		return
+	}
+
+	prefix := r.cfg.Filename + "."
+	backups := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if _, err := time.Parse(rotateTimeFormat, strings.TrimPrefix(m, prefix)); err == nil {
+			backups = append(backups, m)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	for i, b := range backups {
+		if r.cfg.MaxBackups > 0 && i >= r.cfg.MaxBackups {
+			os.Remove(b)
+			continue
+		}
+		if r.cfg.MaxAge > 0 {
+			if info, err := os.Stat(b); err == nil && time.Since(info.ModTime()) > r.cfg.MaxAge {
+				os.Remove(b)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package fastrest
+
+import (
+	"time"
+
+	"fastrest/context"
+	"fastrest/middlewares"
+)
+
+type CachePolicy struct {
+	TTL   time.Duration
+	Store middlewares.CacheStore
+}
+
+type Policy struct {
+	Auth   *middlewares.AuthConfig
+	Scopes []string
+	CORS   *middlewares.CORSConfig
+	Cache  *CachePolicy
+}
+
+func (p Policy) Middleware() []context.Middleware {
+	return p.middleware(nil)
+}
+
+func (p Policy) middleware(r *Router) []context.Middleware {
+	var mw []context.Middleware
+	if p.CORS != nil {
+		mw = append(mw, middlewares.CORS(p.CORS))
+	}
+	if p.Auth != nil {
+		mw = append(mw, middlewares.Auth(p.Auth))
+	}
+	if len(p.Scopes) > 0 {
+		mw = append(mw, middlewares.RequireScopes(p.Scopes...))
+	}
+	if p.Cache != nil {
+		var opts []middlewares.CacheOption
+		if p.Cache.Store != nil {
+			opts = append(opts, middlewares.WithCacheStore(p.Cache.Store))
+		}
+		mw = append(mw, r.CacheMiddleware(p.Cache.TTL, opts...))
+	}
+	return mw
+}
+
+func (rt *Route) Policy(p Policy) *Route {
+	rt.policy = &p
+	rt.middleware = append(rt.middleware, p.middleware(rt.router)...)
+	rt.chain.Store(nil)
+	if p.CORS != nil && rt.router != nil && rt.Method != "OPTIONS" {
+		rt.router.preflight(rt.Host, rt.Path, p.CORS)
+	}
+	return rt
+}
+
+func (r *Router) preflight(host, path string, cors *middlewares.CORSConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rt := range *r.routes {
+		if rt.Method == "OPTIONS" && rt.Host == host && rt.Path == path {
+			return
+		}
+	}
+	rt := newRoute("OPTIONS", host, path)
+	rt.Handlers = []context.Handler{func(c *context.Ctx) error { return c.NoContent() }}
+	rt.middleware = []context.Middleware{middlewares.CORS(cors)}
+	rt.router = r
+	*r.routes = append(*r.routes, rt)
+}
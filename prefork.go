@@ -0,0 +1,152 @@
+package fastrest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+const preforkChildEnv = EnvPrefix + "PREFORK_CHILD"
+
+const preforkFailureWindow = time.Minute
+
+var ErrPreforkUnsupported = errors.New("fastrest: prefork is not supported on this platform")
+
+type preforkExit struct {
+	pid int
+	err error
+}
+
+func IsChild() bool {
+	return os.Getenv(preforkChildEnv) == "1"
+}
+
+func (a *App) listen() (net.Listener, error) {
+	if a.config.Prefork {
+		return reuseportListen("tcp4", a.config.Addr)
+	}
+	return net.Listen("tcp4", a.config.Addr)
+}
+
+func (a *App) preforkMaster() error {
+	ln, err := reuseportListen("tcp4", a.config.Addr)
+	if err != nil {
+		return err
+	}
+	ln.Close()
+
+	n := runtime.GOMAXPROCS(0)
+	threshold := n / 2
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	children := make(map[int]*exec.Cmd, n)
+	exits := make(chan preforkExit, n)
+	spawn := func() error {
+		cmd := exec.Command(os.Args[0], os.Args[1:]...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Env = append(os.Environ(), preforkChildEnv+"=1")
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		children[cmd.Process.Pid] = cmd
+		go func() {
+			exits <- preforkExit{pid: cmd.Process.Pid, err: cmd.Wait()}
+		}()
+		return nil
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	for i := 0; i < n; i++ {
+		if err := spawn(); err != nil {
+			a.stopChildren(children, exits)
+			return fmt.Errorf("fastrest: prefork: %w", err)
+		}
+	}
+	a.logger.Info("prefork master started", "pid", os.Getpid(), "children", n, "addr", a.config.Addr)
+
+	var failures []time.Time
+	for {
+		select {
+		case e := <-exits:
+			delete(children, e.pid)
+			reason := "exited"
+			if e.err != nil {
+				reason = e.err.Error()
+			}
+			a.logger.Warn("prefork child exited", "pid", e.pid, "reason", reason)
+
+			now := a.clock.Now()
+			recent := failures[:0]
+			for _, at := range failures {
+				if now.Sub(at) < preforkFailureWindow {
+					recent = append(recent, at)
+				}
+			}
+			failures = append(recent, now)
+			if len(failures) > threshold {
+				a.logger.Error("prefork master giving up", "failures", len(failures), "window", preforkFailureWindow.String(), "reason", reason)
+				a.stopChildren(children, exits)
+				return fmt.Errorf("fastrest: prefork: children exited %d times within %s, giving up: %s", len(failures), preforkFailureWindow, reason)
+			}
+			if err := spawn(); err != nil {
+				a.logger.Error("prefork master giving up", "reason", err.Error())
+				a.stopChildren(children, exits)
+				return fmt.Errorf("fastrest: prefork: %w", err)
+			}
+		case <-quit:
+			a.emit(EventShutdownInitiated, map[string]interface{}{"children": len(children)})
+			began := a.clock.Now()
+			a.stopChildren(children, exits)
+			a.emit(EventDrained, map[string]interface{}{"duration_ms": float64(a.clock.Since(began)) / float64(time.Millisecond)})
+			return nil
+		}
+	}
+}
+
+func (a *App) stopChildren(children map[int]*exec.Cmd, exits <-chan preforkExit) {
+	for _, cmd := range children {
+		cmd.Process.Signal(syscall.SIGTERM)
+	}
+
+	timer := time.NewTimer(a.config.GracefulTimeout + time.Second)
+	defer timer.Stop()
+	for len(children) > 0 {
+		select {
+		case e := <-exits:
+			delete(children, e.pid)
+		case <-timer.C:
+			a.logger.Warn("prefork children did not exit before timeout, killing", "remaining", len(children))
+			for _, cmd := range children {
+				cmd.Process.Kill()
+			}
+		}
+	}
+}
+
+func watchMaster(quit chan<- os.Signal) {
+	ppid := os.Getppid()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for range ticker.C {
+		if os.Getppid() != ppid {
+			select {
+			case quit <- syscall.SIGTERM:
+			default:
+			}
+			return
+		}
+	}
+}
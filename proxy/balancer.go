@@ -0,0 +1,49 @@
+package proxy
+
+import "sync/atomic"
+
+type Balancer interface {
+	Pick(upstreams []*Upstream) *Upstream
+}
+
+type roundRobin struct {
+	next uint64
+}
+
+func RoundRobin() Balancer {
+	return &roundRobin{}
+}
+
+func (b *roundRobin) Pick(upstreams []*Upstream) *Upstream {
+	n := uint64(len(upstreams))
+	if n == 0 {
+		return nil
+	}
+	start := atomic.AddUint64(&b.next, 1) - 1
+	for i := uint64(0); i < n; i++ {
+		up := upstreams[(start+i)%n]
+		if up.Healthy() {
+			return up
+		}
+	}
+	return nil
+}
+
+type leastConn struct{}
+
+func LeastConn() Balancer {
+	return leastConn{}
+}
+
+func (leastConn) Pick(upstreams []*Upstream) *Upstream {
+	var best *Upstream
+	for _, up := range upstreams {
+		if !up.Healthy() {
+			continue
+		}
+		if best == nil || up.ActiveConns() < best.ActiveConns() {
+			best = up
+		}
+	}
+	return best
+}
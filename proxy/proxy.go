@@ -0,0 +1,257 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"fastrest/constant"
+	"fastrest/context"
+	"fastrest/pkg/logging"
+)
+
+var ErrNoTargets = errors.New("proxy: at least one target is required")
+
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+type Config struct {
+	Targets        []string
+	Balancer       Balancer
+	StripPrefix    string
+	Rewrite        func(path string) string
+	Timeout        time.Duration
+	HealthPath     string
+	HealthInterval time.Duration
+	Logger         logging.Logger
+}
+
+type Upstream struct {
+	target  *url.URL
+	client  *fasthttp.HostClient
+	active  int64
+	healthy int32
+}
+
+func (u *Upstream) URL() string {
+	return u.target.String()
+}
+
+func (u *Upstream) Healthy() bool {
+	return atomic.LoadInt32(&u.healthy) == 1
+}
+
+func (u *Upstream) ActiveConns() int64 {
+	return atomic.LoadInt64(&u.active)
+}
+
+func (u *Upstream) setHealthy(healthy bool) bool {
+	var v int32
+	if healthy {
+		v = 1
+	}
+	return atomic.SwapInt32(&u.healthy, v) != v
+}
+
+type Proxy struct {
+	cfg       Config
+	upstreams []*Upstream
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+func New(cfg *Config) (*Proxy, error) {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if len(c.Targets) == 0 {
+		return nil, ErrNoTargets
+	}
+	if c.Balancer == nil {
+		c.Balancer = RoundRobin()
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 30 * time.Second
+	}
+	if c.HealthPath == "" {
+		c.HealthPath = "/health"
+	}
+	if c.Logger == nil {
+		c.Logger = logging.NewLogger()
+	}
+
+	p := &Proxy{cfg: c, stop: make(chan struct{})}
+	for _, target := range c.Targets {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("proxy: invalid target %q: %w", target, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("proxy: invalid target %q: expected http(s)://host[:port]", target)
+		}
+
+		isTLS := u.Scheme == "https"
+		p.upstreams = append(p.upstreams, &Upstream{
+			target: u,
+			client: &fasthttp.HostClient{
+				Addr:  fasthttp.AddMissingPort(u.Host, isTLS),
+				IsTLS: isTLS,
+			},
+			healthy: 1,
+		})
+	}
+
+	if c.HealthInterval > 0 {
+		go p.healthLoop()
+	}
+	return p, nil
+}
+
+func (p *Proxy) Upstreams() []*Upstream {
+	return append([]*Upstream{}, p.upstreams...)
+}
+
+func (p *Proxy) Close() {
+	p.stopOnce.Do(func() { close(p.stop) })
+}
+
+func (p *Proxy) Handler() context.Handler {
+	return p.serve
+}
+
+func (p *Proxy) serve(c *context.Ctx) error {
+	up := p.cfg.Balancer.Pick(p.upstreams)
+	if up == nil {
+		return c.SendError(constant.StatusServiceUnavailable, "no healthy upstream")
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	c.Request.CopyTo(req)
+	req.SetRequestURI(p.upstreamURI(up, c))
+	req.Header.SetHost(up.target.Host)
+	for _, h := range hopHeaders {
+		req.Header.Del(h)
+	}
+
+	forwardedFor := c.PeerIP()
+	if prior := c.Get("X-Forwarded-For"); prior != "" {
+		forwardedFor = prior + ", " + forwardedFor
+	}
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	req.Header.Set("X-Forwarded-Host", string(c.Host()))
+	if c.IsTLS() {
+		req.Header.Set("X-Forwarded-Proto", "https")
+	} else {
+		req.Header.Set("X-Forwarded-Proto", "http")
+	}
+
+	atomic.AddInt64(&up.active, 1)
+	err := up.client.DoTimeout(req, resp, p.cfg.Timeout)
+	atomic.AddInt64(&up.active, -1)
+
+	if err != nil {
+		c.Logger.Warn("proxy upstream request failed", "upstream", up.URL(), "error", err.Error())
+		if p.cfg.HealthInterval > 0 && up.setHealthy(false) {
+			p.cfg.Logger.Warn("proxy upstream marked unhealthy", "upstream", up.URL())
+		}
+		if errors.Is(err, fasthttp.ErrTimeout) {
+			return c.SendError(constant.StatusGatewayTimeout, "upstream timeout")
+		}
+		return c.SendError(constant.StatusBadGateway, "bad gateway")
+	}
+
+	for _, h := range hopHeaders {
+		resp.Header.Del(h)
+	}
+	for k, v := range resp.Header.All() {
+		if string(k) == "Content-Length" {
+			continue
+		}
+		c.Response.Header.SetBytesKV(k, v)
+	}
+	c.Response.SetStatusCode(resp.StatusCode())
+	c.Response.SetBody(resp.Body())
+	return nil
+}
+
+func (p *Proxy) upstreamURI(up *Upstream, c *context.Ctx) string {
+	path := c.Path()
+	if prefix := strings.TrimSuffix(p.cfg.StripPrefix, "/"); prefix != "" {
+		if path == prefix {
+			path = "/"
+		} else if strings.HasPrefix(path, prefix+"/") {
+			path = path[len(prefix):]
+		}
+	}
+	if p.cfg.Rewrite != nil {
+		path = p.cfg.Rewrite(path)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	uri := up.target.Scheme + "://" + up.target.Host + strings.TrimSuffix(up.target.Path, "/") + (&url.URL{Path: path}).EscapedPath()
+	if query := c.QueryArgs().QueryString(); len(query) > 0 {
+		uri += "?" + string(query)
+	}
+	return uri
+}
+
+func (p *Proxy) healthLoop() {
+	ticker := time.NewTicker(p.cfg.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, up := range p.upstreams {
+				p.probe(up)
+			}
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *Proxy) probe(up *Upstream) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(up.target.Scheme + "://" + up.target.Host + strings.TrimSuffix(up.target.Path, "/") + p.cfg.HealthPath)
+	req.Header.SetMethod("GET")
+
+	timeout := p.cfg.HealthInterval
+	if timeout > 5*time.Second {
+		timeout = 5 * time.Second
+	}
+	err := up.client.DoTimeout(req, resp, timeout)
+	healthy := err == nil && resp.StatusCode() >= 200 && resp.StatusCode() < 300
+
+	if up.setHealthy(healthy) {
+		if healthy {
+			p.cfg.Logger.Info("proxy upstream recovered", "upstream", up.URL())
+		} else {
+			p.cfg.Logger.Warn("proxy upstream marked unhealthy", "upstream", up.URL())
+		}
+	}
+}
@@ -0,0 +1,3 @@
+package fastrest
+
+const soReusePort = 0xf
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd
+
+package fastrest
+
+import "net"
+
+func reuseportListen(network, addr string) (net.Listener, error) {
+	return nil, ErrPreforkUnsupported
+}
@@ -0,0 +1,25 @@
+//go:build linux || darwin || freebsd
+
+package fastrest
+
+import (
+	stdctx "context"
+	"net"
+	"syscall"
+)
+
+func reuseportListen(network, addr string) (net.Listener, error) {
+	lc := net.ListenConfig{
+		Control: func(_, _ string, rc syscall.RawConn) error {
+			var serr error
+			err := rc.Control(func(fd uintptr) {
+				serr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, 1)
+			})
+			if err != nil {
+				return err
+			}
+			return serr
+		},
+	}
+	return lc.Listen(stdctx.Background(), network, addr)
+}
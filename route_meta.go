@@ -0,0 +1,62 @@
+package fastrest
+
+import (
+	"net/http"
+	"time"
+
+	"fastrest/context"
+)
+
+type routeMeta struct {
+	summary     string
+	description string
+	tags        []string
+	deprecated  bool
+	since       time.Time
+	sunset      time.Time
+}
+
+func (rt *Route) Summary(summary string) *Route {
+	rt.meta.summary = summary
+	return rt
+}
+
+func (rt *Route) Description(description string) *Route {
+	rt.meta.description = description
+	return rt
+}
+
+func (rt *Route) Tags(tags ...string) *Route {
+	rt.meta.tags = append(rt.meta.tags, tags...)
+	return rt
+}
+
+func (rt *Route) Deprecated(since, sunset time.Time) *Route {
+	rt.meta.deprecated = true
+	rt.meta.since = since
+	rt.meta.sunset = sunset
+	rt.chain.Store(nil)
+	return rt
+}
+
+func (a *App) deprecatedHandler(rt *Route, next context.Handler) context.Handler {
+	deprecation := "true"
+	if !rt.meta.since.IsZero() {
+		deprecation = rt.meta.since.UTC().Format(http.TimeFormat)
+	}
+	sunset := ""
+	if !rt.meta.sunset.IsZero() {
+		sunset = rt.meta.sunset.UTC().Format(http.TimeFormat)
+	}
+	requests := a.metrics.Counter("deprecated_route_requests_total",
+		"method", rt.Method, "path", rt.Path, "successor", "")
+
+	return func(c *context.Ctx) error {
+		requests.Inc()
+		c.Set("Deprecation", deprecation)
+		if sunset != "" {
+			c.Set("Sunset", sunset)
+		}
+		return next(c)
+	}
+}
@@ -1,24 +1,72 @@
 package fastrest
 
 import (
+	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"fastrest/context"
+	"fastrest/middlewares"
 )
 
 type Route struct {
 	Method     string
+	Host       string
 	Path       string
+	Name       string
 	Handlers   []context.Handler
 	middleware []context.Middleware
+	policy     *Policy
+	meta       routeMeta
+	router     *Router
+	segments   []string
+	wildcard   bool
+	chain      atomic.Pointer[compiledChain]
+}
+
+type compiledChain struct {
+	gen     uint64
+	handler context.Handler
+}
+
+type routeParam struct {
+	key   string
+	value string
+}
+
+var paramPool = sync.Pool{
+	New: func() interface{} {
+		params := make([]routeParam, 0, 8)
+		return &params
+	},
+}
+
+func newRoute(method, host, path string) *Route {
+	segments := strings.Split(path, "/")
+	return &Route{
+		Method:   method,
+		Host:     host,
+		Path:     path,
+		segments: segments,
+		wildcard: segments[len(segments)-1] == "*",
+	}
+}
+
+func (rt *Route) Named(name string) *Route {
+	rt.Name = name
+	return rt
 }
 
 type Router struct {
+	host       string
 	prefix     string
 	routes     *[]*Route
 	middleware []context.Middleware
 	mu         *sync.RWMutex
+	fold       bool
+	cache      *middlewares.CacheInvalidator
 }
 
 func newRouter(prefix string) *Router {
@@ -31,93 +79,226 @@ func newRouter(prefix string) *Router {
 	}
 }
 
+func NewRouter() *Router {
+	return newRouter("")
+}
+
 func (r *Router) Group(prefix string) *Router {
 	return &Router{
+		host:       r.host,
 		prefix:     r.prefix + prefix,
 		routes:     r.routes,
 		middleware: append([]context.Middleware{}, r.middleware...),
 		mu:         r.mu,
+		fold:       r.fold,
+		cache:      r.cache,
+	}
+}
+
+func (r *Router) Host(pattern string) *Router {
+	return &Router{
+		host:       strings.ToLower(pattern),
+		prefix:     r.prefix,
+		routes:     r.routes,
+		middleware: append([]context.Middleware{}, r.middleware...),
+		mu:         r.mu,
+		fold:       r.fold,
+		cache:      r.cache,
 	}
 }
 
+func (r *Router) CacheMiddleware(ttl time.Duration, opts ...middlewares.CacheOption) context.Middleware {
+	if r != nil && r.cache != nil {
+		opts = append([]middlewares.CacheOption{middlewares.WithCacheInvalidator(r.cache)}, opts...)
+	}
+	return middlewares.Cache(ttl, opts...)
+}
+
 func (r *Router) Use(mw ...context.Middleware) {
 	r.middleware = append(r.middleware, mw...)
 }
 
-func (r *Router) add(method, path string, handlers ...context.Handler) {
-	fullPath := r.prefix + path
-	route := &Route{
-		Method:     method,
-		Path:       fullPath,
-		Handlers:   handlers,
-		middleware: append([]context.Middleware{}, r.middleware...),
-	}
+func (r *Router) add(method, path string, handlers ...context.Handler) *Route {
+	route := newRoute(method, r.host, r.prefix+path)
+	route.Handlers = handlers
+	route.middleware = append([]context.Middleware{}, r.middleware...)
+	route.router = r
 	r.mu.Lock()
 	*r.routes = append(*r.routes, route)
 	r.mu.Unlock()
+	return route
 }
 
-func (r *Router) find(method, path string) (*Route, map[string]string) {
+func (r *Router) find(method, host, path string, params []routeParam) (*Route, []routeParam) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	for _, route := range *r.routes {
-		if route.Method != method {
+		if route.Method != method || route.Host == "" {
+			continue
+		}
+		matched, ok := matchPath(route, path, params, r.fold)
+		if !ok {
+			continue
+		}
+		if matched, ok = matchHost(route.Host, host, matched); ok {
+			return route, matched
+		}
+	}
+	for _, route := range *r.routes {
+		if route.Method != method || route.Host != "" {
 			continue
 		}
-		params, ok := matchPath(route.Path, path)
-		if ok {
-			return route, params
+		if matched, ok := matchPath(route, path, params, r.fold); ok {
+			return route, matched
 		}
 	}
-	return nil, nil
+	return nil, params
 }
 
-func matchPath(pattern, path string) (map[string]string, bool) {
-	patternParts := strings.Split(pattern, "/")
-	pathParts := strings.Split(path, "/")
-
-	if len(patternParts) != len(pathParts) {
-		return nil, false
+func matchHost(pattern, host string, params []routeParam) ([]routeParam, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	patternParts := strings.Split(pattern, ".")
+	hostParts := strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".")
+	if len(patternParts) != len(hostParts) {
+		return params, false
 	}
 
-	params := make(map[string]string)
+	n := len(params)
 	for i, part := range patternParts {
 		if strings.HasPrefix(part, ":") {
-			params[part[1:]] = pathParts[i]
-		} else if part != pathParts[i] {
-			return nil, false
+			if hostParts[i] == "" {
+				return params[:n], false
+			}
+			if !hasParam(params[:n], part[1:]) {
+				params = append(params, routeParam{key: part[1:], value: hostParts[i]})
+			}
+		} else if part != "*" && part != hostParts[i] {
+			return params[:n], false
+		}
+	}
+	return params, true
+}
+
+func matchPath(route *Route, path string, params []routeParam, fold bool) ([]routeParam, bool) {
+	n := len(params)
+	rest, done := path, false
+	last := len(route.segments) - 1
+	for i, part := range route.segments {
+		if route.wildcard && i == last {
+			value := ""
+			if !done {
+				value = rest
+			}
+			return append(params, routeParam{key: "*", value: value}), true
+		}
+		if done {
+			return params[:n], false
+		}
+		var segment string
+		var more bool
+		segment, rest, more = strings.Cut(rest, "/")
+		done = !more
+		if strings.HasPrefix(part, ":") {
+			params = append(params, routeParam{key: part[1:], value: segment})
+		} else if part != segment && !(fold && strings.EqualFold(part, segment)) {
+			return params[:n], false
 		}
 	}
+	if !done {
+		return params[:n], false
+	}
 	return params, true
 }
 
-func (r *Router) GET(path string, handlers ...context.Handler) {
-	r.add("GET", path, handlers...)
+func hasParam(params []routeParam, key string) bool {
+	for _, p := range params {
+		if p.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Router) GET(path string, handlers ...context.Handler) *Route {
+	return r.add("GET", path, handlers...)
+}
+
+func (r *Router) POST(path string, handlers ...context.Handler) *Route {
+	return r.add("POST", path, handlers...)
+}
+
+func (r *Router) PUT(path string, handlers ...context.Handler) *Route {
+	return r.add("PUT", path, handlers...)
+}
+
+func (r *Router) PATCH(path string, handlers ...context.Handler) *Route {
+	return r.add("PATCH", path, handlers...)
+}
+
+func (r *Router) DELETE(path string, handlers ...context.Handler) *Route {
+	return r.add("DELETE", path, handlers...)
 }
 
-func (r *Router) POST(path string, handlers ...context.Handler) {
-	r.add("POST", path, handlers...)
+func (r *Router) HEAD(path string, handlers ...context.Handler) *Route {
+	return r.add("HEAD", path, handlers...)
 }
 
-func (r *Router) PUT(path string, handlers ...context.Handler) {
-	r.add("PUT", path, handlers...)
+func (r *Router) OPTIONS(path string, handlers ...context.Handler) *Route {
+	return r.add("OPTIONS", path, handlers...)
 }
 
-func (r *Router) PATCH(path string, handlers ...context.Handler) {
-	r.add("PATCH", path, handlers...)
+func (r *Router) Handle(method, path string, handlers ...context.Handler) *Route {
+	return r.add(strings.ToUpper(method), path, handlers...)
 }
 
-func (r *Router) DELETE(path string, handlers ...context.Handler) {
-	r.add("DELETE", path, handlers...)
+func (r *Router) Mount(prefix string, sub *Router) {
+	r.mount(prefix, sub, nil)
 }
 
-func (r *Router) HEAD(path string, handlers ...context.Handler) {
-	r.add("HEAD", path, handlers...)
+func (r *Router) mount(prefix string, sub *Router, mw []context.Middleware) {
+	sub.mu.RLock()
+	routes := append([]*Route{}, *sub.routes...)
+	sub.mu.RUnlock()
+
+	base := r.prefix + strings.TrimSuffix(prefix, "/")
+	for _, rt := range routes {
+		path := base + rt.Path
+		if rt.Path == "/" && base != "" {
+			path = base
+		}
+
+		middleware := make([]context.Middleware, 0, len(r.middleware)+len(mw)+len(rt.middleware))
+		middleware = append(middleware, r.middleware...)
+		middleware = append(middleware, mw...)
+		middleware = append(middleware, rt.middleware...)
+
+		host := rt.Host
+		if host == "" {
+			host = r.host
+		}
+
+		route := newRoute(rt.Method, host, path)
+		route.Name = rt.Name
+		route.Handlers = rt.Handlers
+		route.middleware = middleware
+		route.policy = rt.policy
+		route.meta = rt.meta
+		route.meta.tags = append([]string(nil), rt.meta.tags...)
+		route.router = r
+
+		r.mu.Lock()
+		*r.routes = append(*r.routes, route)
+		r.mu.Unlock()
+	}
 }
 
-func (r *Router) OPTIONS(path string, handlers ...context.Handler) {
-	r.add("OPTIONS", path, handlers...)
+func (r *Router) Any(path string, handlers ...context.Handler) {
+	for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"} {
+		r.add(method, path, handlers...)
+	}
 }
 
 func (r *Router) Count() int {
@@ -0,0 +1,63 @@
+package fastrest
+
+import (
+	"io"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+
+	"fastrest/context"
+)
+
+func benchApp(b *testing.B) *App {
+	b.Helper()
+	app := New(&Config{Logger: NewJSONLogger(io.Discard)})
+	ok := func(c *context.Ctx) error {
+		c.Response.SetStatusCode(200)
+		return nil
+	}
+	for _, path := range []string{"/", "/health", "/users", "/users/:id", "/users/:id/posts/:post", "/orgs/:org/repos", "/files/*"} {
+		app.GET(path, ok)
+		app.POST(path, ok)
+	}
+	return app
+}
+
+func benchFind(b *testing.B, path string) {
+	app := benchApp(b)
+	params := make([]routeParam, 0, 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		route, matched := app.router.find("GET", "", path, params[:0])
+		if route == nil {
+			b.Fatalf("no route for %s", path)
+		}
+		params = matched
+	}
+}
+
+func BenchmarkRouterFindStatic(b *testing.B) {
+	benchFind(b, "/health")
+}
+
+func BenchmarkRouterFindParams(b *testing.B) {
+	benchFind(b, "/users/42/posts/7")
+}
+
+func BenchmarkRouterFindWildcard(b *testing.B) {
+	benchFind(b, "/files/a/b/c.txt")
+}
+
+func BenchmarkServeParams(b *testing.B) {
+	app := benchApp(b)
+	var fctx fasthttp.RequestCtx
+	fctx.Request.Header.SetMethod("GET")
+	fctx.Request.SetRequestURI("/users/42/posts/7")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fctx.Response.Reset()
+		app.handleRequest(&fctx)
+	}
+}
@@ -0,0 +1,83 @@
+package fastrest
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+const RoutesPath = "/_routes"
+
+type RouteInfo struct {
+	Method     string     `json:"method"`
+	Host       string     `json:"host,omitempty"`
+	Path       string     `json:"path"`
+	Name       string     `json:"name,omitempty"`
+	Middleware int        `json:"middleware"`
+	Handler    string     `json:"handler"`
+	Handlers   int        `json:"handlers"`
+	Summary    string     `json:"summary,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	Deprecated bool       `json:"deprecated,omitempty"`
+	Sunset     *time.Time `json:"sunset,omitempty"`
+}
+
+func (a *App) Routes() []RouteInfo {
+	a.router.mu.RLock()
+	routes := append([]*Route{}, *a.router.routes...)
+	a.router.mu.RUnlock()
+
+	infos := make([]RouteInfo, len(routes))
+	for i, rt := range routes {
+		infos[i] = RouteInfo{
+			Method:     rt.Method,
+			Host:       rt.Host,
+			Path:       rt.Path,
+			Name:       rt.Name,
+			Middleware: len(a.middleware) + len(rt.middleware),
+			Handlers:   len(rt.Handlers),
+			Summary:    rt.meta.summary,
+			Tags:       rt.meta.tags,
+			Deprecated: rt.meta.deprecated,
+		}
+		if !rt.meta.sunset.IsZero() {
+			sunset := rt.meta.sunset
+			infos[i].Sunset = &sunset
+		}
+		if len(rt.Handlers) > 0 {
+			infos[i].Handler = handlerName(rt.Handlers[len(rt.Handlers)-1])
+		}
+	}
+	return infos
+}
+
+func (a *App) RouteByName(name string) (RouteInfo, bool) {
+	for _, info := range a.Routes() {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return RouteInfo{}, false
+}
+
+func handlerName(h context.Handler) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	return strings.TrimSuffix(fn.Name(), "-fm")
+}
+
+func (a *App) isDevelopment() bool {
+	return a.config.Env == "development" || a.config.Env == "dev"
+}
+
+func (a *App) registerRoutesEndpoint() {
+	a.GET(RoutesPath, func(c *context.Ctx) error {
+		return c.RawJSON(constant.StatusOK, a.Routes())
+	}).Named("fastrest.routes")
+}
@@ -0,0 +1,255 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"fastrest/metrics"
+	"fastrest/pkg/clock"
+	"fastrest/pkg/logging"
+)
+
+var ErrSchedulerClosed = errors.New("schedule: scheduler is shut down")
+
+type Job func(ctx context.Context) error
+
+type Config struct {
+	Logger   logging.Logger
+	Metrics  *metrics.Metrics
+	Clock    clock.Clock
+	Location *time.Location
+}
+
+type Option func(*entry)
+
+func WithName(name string) Option {
+	return func(e *entry) {
+		e.name = name
+	}
+}
+
+type Scheduler struct {
+	cfg        Config
+	ctx        context.Context
+	cancel     context.CancelFunc
+	loopCtx    context.Context
+	loopCancel context.CancelFunc
+	loops      sync.WaitGroup
+	mu         sync.Mutex
+	closed     bool
+	entries    []*entry
+}
+
+type entry struct {
+	name     string
+	spec     string
+	schedule *Schedule
+	job      Job
+
+	mu      sync.Mutex
+	running bool
+	next    time.Time
+	lastRun time.Time
+	lastErr string
+	runs    int64
+	skipped int64
+}
+
+type EntryStatus struct {
+	Name      string    `json:"name"`
+	Spec      string    `json:"spec"`
+	Running   bool      `json:"running"`
+	Next      time.Time `json:"next"`
+	LastRun   time.Time `json:"last_run,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	Runs      int64     `json:"runs"`
+	Skipped   int64     `json:"skipped"`
+}
+
+func New(cfg *Config) *Scheduler {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Logger == nil {
+		c.Logger = logging.NewLogger()
+	}
+	if c.Clock == nil {
+		c.Clock = clock.System
+	}
+	if c.Location == nil {
+		c.Location = time.Local
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	loopCtx, loopCancel := context.WithCancel(ctx)
+	return &Scheduler{
+		cfg:        c,
+		ctx:        ctx,
+		cancel:     cancel,
+		loopCtx:    loopCtx,
+		loopCancel: loopCancel,
+	}
+}
+
+func (s *Scheduler) Add(spec string, job Job, opts ...Option) error {
+	sched, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+
+	e := &entry{name: spec, spec: spec, schedule: sched, job: job}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSchedulerClosed
+	}
+	s.entries = append(s.entries, e)
+	s.loops.Add(1)
+	go s.loop(e)
+	return nil
+}
+
+func (s *Scheduler) Entries() []EntryStatus {
+	s.mu.Lock()
+	entries := append([]*entry{}, s.entries...)
+	s.mu.Unlock()
+
+	out := make([]EntryStatus, len(entries))
+	for i, e := range entries {
+		e.mu.Lock()
+		out[i] = EntryStatus{
+			Name:      e.name,
+			Spec:      e.spec,
+			Running:   e.running,
+			Next:      e.next,
+			LastRun:   e.lastRun,
+			LastError: e.lastErr,
+			Runs:      e.runs,
+			Skipped:   e.skipped,
+		}
+		e.mu.Unlock()
+	}
+	return out
+}
+
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.loopCancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.loops.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(e *entry) {
+	defer s.loops.Done()
+
+	now := s.cfg.Clock.Now().In(s.cfg.Location)
+	for {
+		next := e.schedule.Next(now)
+		if next.IsZero() {
+			s.cfg.Logger.Warn("cron job has no upcoming run", "job", e.name, "spec", e.spec)
+			return
+		}
+		e.mu.Lock()
+		e.next = next
+		e.mu.Unlock()
+
+		timer := time.NewTimer(next.Sub(s.cfg.Clock.Now()))
+		select {
+		case <-s.loopCtx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.run(e)
+
+		now = s.cfg.Clock.Now().In(s.cfg.Location)
+		if missed := countMissed(e.schedule, next, now); missed > 0 {
+			e.mu.Lock()
+			e.skipped += int64(missed)
+			e.mu.Unlock()
+			s.cfg.Metrics.Counter("cron_runs_skipped_total", "name", e.name).Add(float64(missed))
+			s.cfg.Logger.Warn("cron job overran its schedule", "job", e.name, "skipped", missed)
+		}
+		if now.Before(next) {
+			now = next
+		}
+	}
+}
+
+func (s *Scheduler) run(e *entry) {
+	e.mu.Lock()
+	e.running = true
+	e.mu.Unlock()
+	s.cfg.Metrics.Gauge("cron_jobs_running").Inc()
+
+	start := s.cfg.Clock.Now()
+	err := s.safeRun(e)
+	duration := s.cfg.Clock.Since(start)
+
+	s.cfg.Metrics.Gauge("cron_jobs_running").Dec()
+	e.mu.Lock()
+	e.running = false
+	e.lastRun = start
+	e.runs++
+	if err != nil {
+		e.lastErr = err.Error()
+	}
+	e.mu.Unlock()
+
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+		s.cfg.Logger.Error("cron job failed", "job", e.name, "error", err.Error())
+	}
+	s.cfg.Metrics.Counter("cron_runs_total", "name", e.name, "outcome", outcome).Inc()
+	s.cfg.Metrics.Histogram("cron_run_duration_seconds", "name", e.name).Observe(duration.Seconds())
+}
+
+func (s *Scheduler) safeRun(e *entry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 4096)
+			buf = buf[:runtime.Stack(buf, false)]
+			s.cfg.Logger.Error("cron job panicked", "job", e.name, "panic", fmt.Sprint(r), "stack", string(buf))
+			err = fmt.Errorf("schedule: job %q panicked: %v", e.name, r)
+		}
+	}()
+	return e.job(s.ctx)
+}
+
+func countMissed(sched *Schedule, from, now time.Time) int {
+	missed := 0
+	for t := sched.Next(from); !t.IsZero() && !t.After(now) && missed < 1000; t = sched.Next(t) {
+		missed++
+	}
+	return missed
+}
@@ -0,0 +1,171 @@
+package fastrest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+const UndoTokenHeader = "X-Undo-Token"
+
+var ErrRecordNotFound = context.ErrRecordNotFound
+
+type SoftDeleteStore interface {
+	SoftDelete(c *context.Ctx, id string) error
+	Restore(c *context.Ctx, id string) error
+}
+
+type SoftDeleteOption func(*softDelete)
+
+type softDelete struct {
+	param  string
+	window time.Duration
+	secret []byte
+}
+
+func WithIDParam(name string) SoftDeleteOption {
+	return func(s *softDelete) {
+		s.param = name
+	}
+}
+
+func WithUndoWindow(d time.Duration) SoftDeleteOption {
+	return func(s *softDelete) {
+		s.window = d
+	}
+}
+
+func WithUndoSecret(secret []byte) SoftDeleteOption {
+	return func(s *softDelete) {
+		s.secret = secret
+	}
+}
+
+func (r *Router) SoftDelete(path string, store SoftDeleteStore, opts ...SoftDeleteOption) {
+	s := &softDelete{param: "id", window: 24 * time.Hour}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if len(s.secret) == 0 {
+		panic("fastrest: SoftDelete " + path + " requires WithUndoSecret")
+	}
+
+	resource := r.prefix + path
+	restorePath := strings.TrimSuffix(path, "/") + "/restore"
+
+	r.DELETE(path, func(c *context.Ctx) error {
+		id := c.Param(s.param)
+		if err := store.SoftDelete(c, id); err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return c.NotFound(err.Error())
+			}
+			return err
+		}
+
+		expires := c.Now().Add(s.window)
+		token := s.sign(resource, undoActor(c), id, expires)
+		c.Set(UndoTokenHeader, token)
+		return c.JSON(constant.StatusAccepted, map[string]interface{}{
+			"id":         id,
+			"deleted":    true,
+			"undo_token": token,
+			"undo_url":   expandPath(r.prefix+restorePath, c.Params),
+			"expires_at": expires.UTC().Format(time.RFC3339),
+		})
+	})
+
+	r.POST(restorePath, func(c *context.Ctx) error {
+		id := c.Param(s.param)
+		token := c.Query("token")
+		if token == "" {
+			token = c.Get(UndoTokenHeader)
+		}
+		if token == "" {
+			return c.BadRequest("undo token required")
+		}
+
+		expires, ok := s.verify(resource, undoActor(c), id, token)
+		if !ok {
+			return c.BadRequest("invalid undo token")
+		}
+		if c.Now().After(expires) {
+			return c.SendError(constant.StatusGone, "undo window has expired")
+		}
+
+		if err := store.Restore(c, id); err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return c.NotFound(err.Error())
+			}
+			return err
+		}
+		return c.OK(map[string]interface{}{"id": id, "deleted": false})
+	})
+}
+
+func undoActor(c *context.Ctx) string {
+	auth := c.GetAuth()
+	if auth == nil || !auth.Valid {
+		return ""
+	}
+	if auth.Subject != "" {
+		return auth.Subject
+	}
+	return auth.Username
+}
+
+func (s *softDelete) mac(resource, actor string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(resource))
+	mac.Write([]byte{0})
+	mac.Write([]byte(actor))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (s *softDelete) sign(resource, actor, id string, expires time.Time) string {
+	payload := id + "." + strconv.FormatInt(expires.Unix(), 10)
+	sig := s.mac(resource, actor, []byte(payload))
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(payload)) + "." + enc.EncodeToString(sig)
+}
+
+func (s *softDelete) verify(resource, actor, id, token string) (time.Time, bool) {
+	encPayload, encSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encPayload)
+	if err != nil {
+		return time.Time{}, false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encSig)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	if !hmac.Equal(sig, s.mac(resource, actor, payload)) {
+		return time.Time{}, false
+	}
+
+	i := strings.LastIndexByte(string(payload), '.')
+	if i < 0 || string(payload[:i]) != id {
+		return time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(string(payload[i+1:]), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(unix, 0), true
+}
+
+func (a *App) SoftDelete(path string, store SoftDeleteStore, opts ...SoftDeleteOption) {
+	a.router.SoftDelete(path, store, opts...)
+}
@@ -0,0 +1,130 @@
+package fastrest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"fastrest/context"
+)
+
+type spaSite struct {
+	app    *App
+	prefix string
+	fsys   fs.FS
+	index  string
+	etags  sync.Map
+
+	mu       sync.Mutex
+	count    int
+	reserved map[string]bool
+}
+
+func (a *App) SPA(prefix string, fsys fs.FS, indexFile string) error {
+	if indexFile == "" {
+		indexFile = "index.html"
+	}
+	indexFile = strings.TrimPrefix(indexFile, "/")
+	if _, err := fs.Stat(fsys, indexFile); err != nil {
+		return fmt.Errorf("fastrest: spa index: %w", err)
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	site := &spaSite{app: a, prefix: prefix, fsys: fsys, index: indexFile, count: -1}
+	route := newRoute("GET", "", prefix+"/*")
+	route.Handlers = []context.Handler{site.serve}
+	route.router = a.router
+	a.spas = append(a.spas, route)
+	return nil
+}
+
+func (a *App) spaRoute(path string, params []routeParam) (*Route, []routeParam) {
+	for _, route := range a.spas {
+		if matched, ok := matchPath(route, path, params, a.router.fold); ok {
+			return route, matched
+		}
+	}
+	return nil, params
+}
+
+func (s *spaSite) serve(c *context.Ctx) error {
+	name := strings.Trim(c.Param("*"), "/")
+	if name != "" && fs.ValidPath(name) {
+		if info, err := fs.Stat(s.fsys, name); err == nil && !info.IsDir() {
+			return s.send(c, name, "public, max-age=3600")
+		}
+	}
+	if name != "" && (path.Ext(name) != "" || s.isReserved(name)) {
+		return c.NotFound("not found")
+	}
+	return s.send(c, s.index, "no-cache")
+}
+
+func (s *spaSite) send(c *context.Ctx, name, cacheControl string) error {
+	etag, err := s.etag(name)
+	if err != nil {
+		return err
+	}
+	f, err := s.fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(data)
+	}
+	c.Set("Cache-Control", cacheControl)
+	return c.ServeContent(name, time.Time{}, etag, content)
+}
+
+func (s *spaSite) etag(name string) (string, error) {
+	if etag, ok := s.etags.Load(name); ok {
+		return etag.(string), nil
+	}
+	data, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	etag, _ := s.etags.LoadOrStore(name, `"`+hex.EncodeToString(sum[:16])+`"`)
+	return etag.(string), nil
+}
+
+func (s *spaSite) isReserved(name string) bool {
+	first, _, _ := strings.Cut(name, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if count := s.app.router.Count(); count != s.count {
+		s.reserved = s.reservedSegments()
+		s.count = count
+	}
+	return s.reserved[first]
+}
+
+func (s *spaSite) reservedSegments() map[string]bool {
+	reserved := make(map[string]bool)
+	for _, info := range s.app.Routes() {
+		rest, ok := strings.CutPrefix(info.Path, s.prefix+"/")
+		if !ok {
+			continue
+		}
+		first, _, _ := strings.Cut(rest, "/")
+		if first != "" && !strings.HasPrefix(first, ":") && first != "*" {
+			reserved[first] = true
+		}
+	}
+	return reserved
+}
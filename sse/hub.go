@@ -0,0 +1,252 @@
+package sse
+
+import (
+	"bufio"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fastrest/context"
+)
+
+type DropPolicy int
+
+const (
+	DropNewest DropPolicy = iota
+	DropOldest
+	Disconnect
+)
+
+type HubConfig struct {
+	BufferSize int
+	DropPolicy DropPolicy
+	KeepAlive  time.Duration
+}
+
+type Hub struct {
+	mu     sync.RWMutex
+	cfg    HubConfig
+	topics map[string]map[*Subscriber]struct{}
+	closed bool
+}
+
+type Subscriber struct {
+	hub     *Hub
+	topics  []string
+	events  chan *Event
+	done    chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	dropped uint64
+}
+
+func NewHub(cfg *HubConfig) *Hub {
+	c := HubConfig{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = 16
+	}
+	if c.KeepAlive == 0 {
+		c.KeepAlive = 15 * time.Second
+	}
+	return &Hub{
+		cfg:    c,
+		topics: make(map[string]map[*Subscriber]struct{}),
+	}
+}
+
+func (h *Hub) Subscribe(topics ...string) *Subscriber {
+	s := &Subscriber{
+		hub:    h,
+		topics: topics,
+		events: make(chan *Event, h.cfg.BufferSize),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		s.close()
+		return s
+	}
+	for _, topic := range topics {
+		subs, ok := h.topics[topic]
+		if !ok {
+			subs = make(map[*Subscriber]struct{})
+			h.topics[topic] = subs
+		}
+		subs[s] = struct{}{}
+	}
+	return s
+}
+
+func (h *Hub) Unsubscribe(s *Subscriber) {
+	h.mu.Lock()
+	h.remove(s)
+	h.mu.Unlock()
+	s.close()
+}
+
+func (h *Hub) remove(s *Subscriber) {
+	for _, topic := range s.topics {
+		if subs, ok := h.topics[topic]; ok {
+			delete(subs, s)
+			if len(subs) == 0 {
+				delete(h.topics, topic)
+			}
+		}
+	}
+}
+
+func (h *Hub) Broadcast(topic string, event *Event) int {
+	h.mu.RLock()
+	subs := make([]*Subscriber, 0, len(h.topics[topic]))
+	for s := range h.topics[topic] {
+		subs = append(subs, s)
+	}
+	h.mu.RUnlock()
+
+	delivered := 0
+	for _, s := range subs {
+		if s.deliver(event, h.cfg.DropPolicy) {
+			delivered++
+		} else if h.cfg.DropPolicy == Disconnect {
+			h.Unsubscribe(s)
+		}
+	}
+	return delivered
+}
+
+func (h *Hub) Subscribers(topic string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.topics[topic])
+}
+
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	var all []*Subscriber
+	for _, subs := range h.topics {
+		for s := range subs {
+			all = append(all, s)
+		}
+	}
+	h.topics = make(map[string]map[*Subscriber]struct{})
+	h.mu.Unlock()
+
+	for _, s := range all {
+		s.close()
+	}
+}
+
+func (h *Hub) Handler(topics func(c *context.Ctx) []string) context.Handler {
+	return func(c *context.Ctx) error {
+		sub := h.Subscribe(topics(c)...)
+		shutdown := c.Done()
+		keepAlive := h.cfg.KeepAlive
+
+		c.Set("Content-Type", "text/event-stream")
+		c.Set("Cache-Control", "no-cache")
+		c.Set("Connection", "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+		c.Response.SetStatusCode(200)
+
+		c.SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer h.Unsubscribe(sub)
+
+			var ticker *time.Ticker
+			var tick <-chan time.Time
+			if keepAlive > 0 {
+				ticker = time.NewTicker(keepAlive)
+				defer ticker.Stop()
+				tick = ticker.C
+			}
+
+			if _, err := w.WriteString(": connected\n\n"); err != nil || w.Flush() != nil {
+				return
+			}
+
+			for {
+				select {
+				case event := <-sub.events:
+					if _, err := event.WriteTo(w); err != nil {
+						return
+					}
+					if err := w.Flush(); err != nil {
+						return
+					}
+				case <-tick:
+					if _, err := w.WriteString(": keep-alive\n\n"); err != nil {
+						return
+					}
+					if err := w.Flush(); err != nil {
+						return
+					}
+				case <-sub.done:
+					return
+				case <-shutdown:
+					return
+				}
+			}
+		})
+		return nil
+	}
+}
+
+func (s *Subscriber) Events() <-chan *Event {
+	return s.events
+}
+
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscriber) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *Subscriber) Close() {
+	s.hub.Unsubscribe(s)
+}
+
+func (s *Subscriber) deliver(event *Event, policy DropPolicy) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+
+	select {
+	case s.events <- event:
+		return true
+	default:
+	}
+
+	atomic.AddUint64(&s.dropped, 1)
+	if policy != DropOldest {
+		return false
+	}
+
+	select {
+	case <-s.events:
+	default:
+	}
+	select {
+	case s.events <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Subscriber) close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
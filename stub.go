@@ -0,0 +1,82 @@
+package fastrest
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+func (r *Router) Respond(path string, status int, body []byte, contentType string) {
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	data := append([]byte{}, body...)
+	r.GET(path, func(c *context.Ctx) error {
+		c.Response.Header.SetContentType(contentType)
+		c.Response.SetStatusCode(status)
+		c.Response.SetBody(data)
+		return nil
+	})
+}
+
+func (r *Router) Stub(path, fixtureFile string) {
+	fixture := &stubFixture{path: fixtureFile}
+	r.GET(path, func(c *context.Ctx) error {
+		data, err := fixture.load()
+		if err != nil {
+			c.GetLogger().Error("failed to load stub fixture", "file", fixtureFile, "error", err.Error())
+			return c.InternalServerError("stub fixture unavailable")
+		}
+		c.Response.Header.SetContentType("application/json")
+		c.Response.SetStatusCode(constant.StatusOK)
+		c.Response.SetBody(data)
+		return nil
+	})
+}
+
+type stubFixture struct {
+	path    string
+	mu      sync.RWMutex
+	data    []byte
+	modTime time.Time
+	size    int64
+}
+
+func (f *stubFixture) load() ([]byte, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.RLock()
+	if f.data != nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		data := f.data
+		f.mu.RUnlock()
+		return data, nil
+	}
+	f.mu.RUnlock()
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.data = data
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	f.mu.Unlock()
+
+	return data, nil
+}
+
+func (a *App) Respond(path string, status int, body []byte, contentType string) {
+	a.router.Respond(path, status, body, contentType)
+}
+
+func (a *App) Stub(path, fixtureFile string) {
+	a.router.Stub(path, fixtureFile)
+}
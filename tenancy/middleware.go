@@ -0,0 +1,115 @@
+package tenancy
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+	"fastrest/metrics"
+)
+
+type Config struct {
+	Resolvers    []Resolver
+	Optional     bool
+	Allow        func(tenant string) bool
+	Limit        Limit
+	Limits       func(tenant string) (Limit, bool)
+	Metrics      *metrics.Metrics
+	MetricsLabel func(tenant string) string
+	Load         Loader
+	CacheTTL     time.Duration
+}
+
+type Tenancy struct {
+	cfg      Config
+	limiter  *limiter
+	settings *settingsCache
+}
+
+func New(cfg *Config) *Tenancy {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.CacheTTL == 0 {
+		c.CacheTTL = 5 * time.Minute
+	}
+	if c.MetricsLabel == nil {
+		c.MetricsLabel = defaultMetricsLabel(c.Allow)
+	}
+	return &Tenancy{
+		cfg:      c,
+		limiter:  newLimiter(),
+		settings: newSettingsCache(c.Load, c.CacheTTL),
+	}
+}
+
+func (t *Tenancy) Resolve(c *context.Ctx) string {
+	for _, resolve := range t.cfg.Resolvers {
+		if tenant := resolve(c); tenant != "" {
+			return tenant
+		}
+	}
+	return ""
+}
+
+func (t *Tenancy) Invalidate(tenant string) {
+	t.settings.invalidate(tenant)
+}
+
+func (t *Tenancy) limitFor(tenant string) Limit {
+	if t.cfg.Limits != nil {
+		if limit, ok := t.cfg.Limits(tenant); ok {
+			return limit
+		}
+	}
+	return t.cfg.Limit
+}
+
+func (t *Tenancy) Middleware() context.Middleware {
+	return func(next context.Handler) context.Handler {
+		return func(c *context.Ctx) error {
+			tenant := t.Resolve(c)
+			if tenant == "" {
+				if t.cfg.Optional {
+					return next(c)
+				}
+				return c.BadRequest("tenant required")
+			}
+			if t.cfg.Allow != nil && !t.cfg.Allow(tenant) {
+				return c.NotFound("unknown tenant")
+			}
+
+			c.SetTenant(tenant)
+			settingsKey.Set(c, t.settings)
+			label := t.cfg.MetricsLabel(tenant)
+
+			if ok, wait := t.limiter.allow(tenant, t.limitFor(tenant), c.Now()); !ok {
+				t.cfg.Metrics.Counter("tenant_rate_limited_total", "tenant", label).Inc()
+				c.Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				return c.SendError(constant.StatusTooManyRequests, "rate limit exceeded")
+			}
+
+			start := c.Now()
+			err := next(c)
+
+			status := context.ErrorStatus(c, err)
+			t.cfg.Metrics.Counter("tenant_requests_total", "tenant", label, "status", strconv.Itoa(status)).Inc()
+			t.cfg.Metrics.Histogram("tenant_request_duration_seconds", "tenant", label).Observe(c.Clock().Since(start).Seconds())
+			return err
+		}
+	}
+}
+
+func defaultMetricsLabel(allow func(string) bool) func(string) string {
+	if allow != nil {
+		return func(tenant string) string { return tenant }
+	}
+	return func(string) string { return "other" }
+}
+
+func Middleware(cfg *Config) context.Middleware {
+	return New(cfg).Middleware()
+}
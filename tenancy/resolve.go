@@ -0,0 +1,51 @@
+package tenancy
+
+import (
+	"net"
+	"strings"
+
+	"fastrest/context"
+)
+
+type Resolver func(c *context.Ctx) string
+
+func FromSubdomain(base string) Resolver {
+	suffix := "." + strings.ToLower(strings.Trim(base, "."))
+	return func(c *context.Ctx) string {
+		host := strings.ToLower(string(c.Host()))
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !strings.HasSuffix(host, suffix) {
+			return ""
+		}
+		sub := strings.TrimSuffix(host, suffix)
+		if sub == "" || strings.Contains(sub, ".") {
+			return ""
+		}
+		return sub
+	}
+}
+
+func FromHeader(name string) Resolver {
+	return func(c *context.Ctx) string {
+		return strings.TrimSpace(c.Get(name))
+	}
+}
+
+func FromClaim(name string) Resolver {
+	return func(c *context.Ctx) string {
+		auth, err := c.MustAuth()
+		if err != nil {
+			return ""
+		}
+		tenant, _ := context.Claim[string](auth, name)
+		return tenant
+	}
+}
+
+func FromParam(name string) Resolver {
+	return func(c *context.Ctx) string {
+		return c.Param(name)
+	}
+}
@@ -0,0 +1,119 @@
+package tenancy
+
+import (
+	stdctx "context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+)
+
+type unknownTenantError struct{}
+
+func (unknownTenantError) Error() string {
+	return "unknown tenant"
+}
+
+func (unknownTenantError) StatusCode() int {
+	return constant.StatusNotFound
+}
+
+var ErrUnknownTenant error = unknownTenantError{}
+
+var ErrNoTenant = errors.New("tenancy: no tenant resolved for request")
+
+type Loader func(ctx stdctx.Context, tenant string) (interface{}, error)
+
+const settingsKey context.Key[*settingsCache] = "tenancy.settings"
+
+type settingsEntry struct {
+	mu      sync.Mutex
+	value   interface{}
+	err     error
+	expires time.Time
+}
+
+type settingsCache struct {
+	load    Loader
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*settingsEntry
+	swept   time.Time
+}
+
+func newSettingsCache(load Loader, ttl time.Duration) *settingsCache {
+	return &settingsCache{load: load, ttl: ttl, entries: make(map[string]*settingsEntry)}
+}
+
+func (s *settingsCache) get(ctx stdctx.Context, tenant string, now time.Time) (interface{}, error) {
+	s.mu.Lock()
+	s.sweep(now)
+	e, ok := s.entries[tenant]
+	if !ok {
+		e = &settingsEntry{}
+		s.entries[tenant] = e
+	}
+	s.mu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.expires.IsZero() && now.Before(e.expires) {
+		return e.value, e.err
+	}
+	value, err := s.load(ctx, tenant)
+	if err != nil && !errors.Is(err, ErrUnknownTenant) {
+		return nil, err
+	}
+	e.value, e.err, e.expires = value, err, now.Add(s.ttl)
+	return value, err
+}
+
+func (s *settingsCache) sweep(now time.Time) {
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+	s.swept = now
+	for tenant, e := range s.entries {
+		if !e.mu.TryLock() {
+			continue
+		}
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			delete(s.entries, tenant)
+		}
+		e.mu.Unlock()
+	}
+}
+
+func (s *settingsCache) invalidate(tenant string) {
+	s.mu.Lock()
+	if tenant == "" {
+		s.entries = make(map[string]*settingsEntry)
+	} else {
+		delete(s.entries, tenant)
+	}
+	s.mu.Unlock()
+}
+
+func Settings[T any](c *context.Ctx) (T, error) {
+	var zero T
+	cache, ok := settingsKey.Get(c)
+	if !ok || cache.load == nil {
+		return zero, errors.New("tenancy: no settings loader configured")
+	}
+	tenant := c.Tenant()
+	if tenant == "" {
+		return zero, ErrNoTenant
+	}
+	value, err := cache.get(c.Context(), tenant, c.Now())
+	if err != nil {
+		return zero, err
+	}
+	v, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("tenancy: settings for %q are %T, not %T", tenant, value, zero)
+	}
+	return v, nil
+}
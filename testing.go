@@ -0,0 +1,57 @@
+package fastrest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+const DefaultTestTimeout = time.Second
+
+func (a *App) Test(req *http.Request, timeout ...time.Duration) (*http.Response, error) {
+	limit := DefaultTestTimeout
+	if len(timeout) > 0 {
+		limit = timeout[0]
+	}
+	if req.RemoteAddr == "" {
+		req.RemoteAddr = "192.0.2.1:1234"
+	}
+	if req.Host == "" && req.URL != nil {
+		req.Host = req.URL.Host
+	}
+
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.serveHTTP(finalOnly{rec}, req)
+	}()
+
+	if limit > 0 {
+		timer := time.NewTimer(limit)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			return nil, fmt.Errorf("fastrest: test request %s %s timed out after %s", req.Method, req.URL.Path, limit)
+		}
+	} else {
+		<-done
+	}
+
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+type finalOnly struct {
+	*httptest.ResponseRecorder
+}
+
+func (w finalOnly) WriteHeader(status int) {
+	if status >= 100 && status < 200 {
+		return
+	}
+	w.ResponseRecorder.WriteHeader(status)
+}
@@ -0,0 +1,154 @@
+package fastrest
+
+import (
+	stdctx "context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fastrest/constant"
+	"fastrest/context"
+	"fastrest/metrics"
+	"fastrest/pkg/clock"
+	"fastrest/pkg/logging"
+)
+
+type TimeoutPolicy struct {
+	Threshold   float64
+	MinRequests int
+	Window      time.Duration
+}
+
+type routeWindow struct {
+	start    time.Time
+	requests int
+	timeouts int
+	degraded bool
+}
+
+type timeoutTracker struct {
+	policy  TimeoutPolicy
+	clock   clock.Clock
+	logger  logging.Logger
+	metrics *metrics.Metrics
+	mu      sync.Mutex
+	routes  map[metrics.RouteKey]*routeWindow
+}
+
+func newTimeoutTracker(policy *TimeoutPolicy, clk clock.Clock, logger logging.Logger, m *metrics.Metrics) *timeoutTracker {
+	p := *policy
+	if p.Threshold <= 0 || p.Threshold > 1 {
+		p.Threshold = 0.1
+	}
+	if p.MinRequests <= 0 {
+		p.MinRequests = 20
+	}
+	if p.Window <= 0 {
+		p.Window = time.Minute
+	}
+	return &timeoutTracker{
+		policy:  p,
+		clock:   clk,
+		logger:  logger,
+		metrics: m,
+		routes:  make(map[metrics.RouteKey]*routeWindow),
+	}
+}
+
+func isTimeout(c *context.Ctx, err error, status int) bool {
+	return c.TimedOut() || errors.Is(err, stdctx.DeadlineExceeded) || status == constant.StatusGatewayTimeout
+}
+
+func (t *timeoutTracker) record(method, path string, timedOut bool) {
+	if t == nil {
+		return
+	}
+	key := metrics.RouteKey{Method: method, Path: path}
+	now := t.clock.Now()
+
+	if timedOut {
+		t.metrics.Counter("route_timeouts_total", "method", method, "path", path).Inc()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.routes[key]
+	if !ok {
+		if !timedOut {
+			return
+		}
+		w = &routeWindow{start: now}
+		t.routes[key] = w
+	}
+
+	if !t.roll(key, w, now, timedOut) {
+		return
+	}
+
+	w.requests++
+	if timedOut {
+		w.timeouts++
+	}
+
+	if !w.degraded && t.exceeded(w) {
+		w.degraded = true
+		t.metrics.Gauge("routes_degraded").Inc()
+		t.logger.Warn("route degraded by sustained timeouts", "route", key.Method+" "+key.Path,
+			"timeouts", w.timeouts, "requests", w.requests, "window", t.policy.Window.String())
+	}
+}
+
+func (t *timeoutTracker) roll(key metrics.RouteKey, w *routeWindow, now time.Time, keep bool) bool {
+	if now.Sub(w.start) < t.policy.Window {
+		return true
+	}
+	if w.degraded && !t.exceeded(w) {
+		w.degraded = false
+		t.metrics.Gauge("routes_degraded").Dec()
+		t.logger.Info("route recovered from sustained timeouts", "route", key.Method+" "+key.Path)
+	}
+	if !w.degraded && w.timeouts == 0 && !keep {
+		delete(t.routes, key)
+		return false
+	}
+	w.start, w.requests, w.timeouts = now, 0, 0
+	return true
+}
+
+func (t *timeoutTracker) exceeded(w *routeWindow) bool {
+	return w.requests >= t.policy.MinRequests &&
+		float64(w.timeouts)/float64(w.requests) >= t.policy.Threshold
+}
+
+func (t *timeoutTracker) degraded() []string {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	var routes []string
+	for key, w := range t.routes {
+		if t.roll(key, w, now, false) && w.degraded {
+			routes = append(routes, key.Method+" "+key.Path)
+		}
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+func (a *App) timeoutCheck(ctx stdctx.Context) error {
+	if routes := a.timeouts.degraded(); len(routes) > 0 {
+		return fmt.Errorf("sustained timeouts on %s", strings.Join(routes, ", "))
+	}
+	return nil
+}
+
+func (a *App) DegradedRoutes() []string {
+	return a.timeouts.degraded()
+}
@@ -0,0 +1,143 @@
+package views
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+var errYieldOutsideLayout = errors.New("views: yield called outside a layout")
+
+type Option func(*Engine)
+
+func WithExtension(ext string) Option {
+	return func(e *Engine) {
+		e.ext = ext
+	}
+}
+
+func WithLayout(name string) Option {
+	return func(e *Engine) {
+		e.layout = name
+	}
+}
+
+func WithReload(reload bool) Option {
+	return func(e *Engine) {
+		e.reload = reload
+	}
+}
+
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(e *Engine) {
+		for name, fn := range funcs {
+			e.funcs[name] = fn
+		}
+	}
+}
+
+type Engine struct {
+	fsys   fs.FS
+	ext    string
+	layout string
+	reload bool
+	funcs  template.FuncMap
+
+	mu        sync.RWMutex
+	templates *template.Template
+}
+
+func New(fsys fs.FS, opts ...Option) (*Engine, error) {
+	e := &Engine{
+		fsys: fsys,
+		ext:  ".html",
+		funcs: template.FuncMap{
+			"yield": func() (template.HTML, error) { return "", errYieldOutsideLayout },
+		},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if err := e.Load(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func NewDir(dir string, opts ...Option) (*Engine, error) {
+	return New(os.DirFS(dir), opts...)
+}
+
+func (e *Engine) SetReload(reload bool) {
+	e.reload = reload
+}
+
+func (e *Engine) Load() error {
+	root := template.New("").Funcs(e.funcs)
+	err := fs.WalkDir(e.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != e.ext {
+			return err
+		}
+		data, err := fs.ReadFile(e.fsys, p)
+		if err != nil {
+			return err
+		}
+		if _, err := root.New(strings.TrimSuffix(p, e.ext)).Parse(string(data)); err != nil {
+			return fmt.Errorf("views: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.templates = root
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *Engine) Render(w io.Writer, name string, data interface{}, layouts ...string) error {
+	if e.reload {
+		if err := e.Load(); err != nil {
+			return err
+		}
+	}
+
+	layout := e.layout
+	if len(layouts) > 0 {
+		layout = layouts[0]
+	}
+
+	e.mu.RLock()
+	tmpl, err := e.templates.Clone()
+	e.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if tmpl.Lookup(name) == nil {
+		return fmt.Errorf("views: template %q not found", name)
+	}
+	if layout == "" {
+		return tmpl.ExecuteTemplate(w, name, data)
+	}
+	if tmpl.Lookup(layout) == nil {
+		return fmt.Errorf("views: layout %q not found", layout)
+	}
+
+	tmpl.Funcs(template.FuncMap{
+		"yield": func() (template.HTML, error) {
+			var buf bytes.Buffer
+			err := tmpl.ExecuteTemplate(&buf, name, data)
+			return template.HTML(buf.String()), err
+		},
+	})
+	return tmpl.ExecuteTemplate(w, layout, data)
+}
@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrInvalidInterval = errors.New("worker: interval must be positive")
+
+type TaskStatus struct {
+	Name         string        `json:"name"`
+	Interval     time.Duration `json:"-"`
+	Every        string        `json:"every,omitempty"`
+	Running      int           `json:"running"`
+	Runs         int64         `json:"runs"`
+	Failures     int64         `json:"failures"`
+	LastRun      time.Time     `json:"last_run,omitempty"`
+	LastDuration string        `json:"last_duration,omitempty"`
+	LastError    string        `json:"last_error,omitempty"`
+	LastFailure  time.Time     `json:"last_failure,omitempty"`
+}
+
+type statusRegistry struct {
+	mu    sync.Mutex
+	tasks map[string]*TaskStatus
+}
+
+func newStatusRegistry() *statusRegistry {
+	return &statusRegistry{tasks: make(map[string]*TaskStatus)}
+}
+
+func (r *statusRegistry) get(name string) *TaskStatus {
+	st, ok := r.tasks[name]
+	if !ok {
+		st = &TaskStatus{Name: name}
+		r.tasks[name] = st
+	}
+	return st
+}
+
+func (r *statusRegistry) register(name string, interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := r.get(name)
+	st.Interval = interval
+	st.Every = interval.String()
+}
+
+func (r *statusRegistry) begin(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.get(name).Running++
+}
+
+func (r *statusRegistry) end(name string, start time.Time, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := r.get(name)
+	st.Running--
+	st.Runs++
+	st.LastRun = start
+	st.LastDuration = duration.String()
+	if err != nil {
+		st.Failures++
+		st.LastError = err.Error()
+		st.LastFailure = start
+	}
+}
+
+func (r *statusRegistry) snapshot() []TaskStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]TaskStatus, 0, len(r.tasks))
+	for _, st := range r.tasks {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (p *Pool) Every(name string, interval time.Duration, task Task) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	p.status.register(name, interval)
+	p.cfg.Metrics.Gauge("background_loops_active").Inc()
+	p.loops.Add(1)
+	go func() {
+		defer p.loops.Done()
+		defer p.cfg.Metrics.Gauge("background_loops_active").Dec()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.loopCtx.Done():
+				return
+			case <-ticker.C:
+				p.run(p.loopCtx, job{name: name, task: task})
+			}
+		}
+	}()
+	return nil
+}
+
+func (p *Pool) Status() []TaskStatus {
+	return p.status.snapshot()
+}
+
+func (p *Pool) Running() int {
+	running := 0
+	for _, st := range p.Status() {
+		running += st.Running
+	}
+	return running
+}
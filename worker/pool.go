@@ -0,0 +1,171 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"fastrest/metrics"
+	"fastrest/pkg/clock"
+	"fastrest/pkg/logging"
+)
+
+var (
+	ErrPoolFull   = errors.New("worker: queue is full")
+	ErrPoolClosed = errors.New("worker: pool is shut down")
+)
+
+type Task func(ctx context.Context) error
+
+type Config struct {
+	Workers   int
+	QueueSize int
+	Logger    logging.Logger
+	Metrics   *metrics.Metrics
+	Clock     clock.Clock
+}
+
+type Pool struct {
+	cfg        Config
+	queue      chan job
+	ctx        context.Context
+	cancel     context.CancelFunc
+	loopCtx    context.Context
+	loopCancel context.CancelFunc
+	wg         sync.WaitGroup
+	loops      sync.WaitGroup
+	mu         sync.RWMutex
+	closed     bool
+	started    sync.Once
+	status     *statusRegistry
+}
+
+type job struct {
+	name string
+	task Task
+}
+
+func New(cfg *Config) *Pool {
+	c := Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Workers <= 0 {
+		c.Workers = runtime.NumCPU()
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = c.Workers * 64
+	}
+	if c.Logger == nil {
+		c.Logger = logging.NewLogger()
+	}
+	if c.Clock == nil {
+		c.Clock = clock.System
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	loopCtx, loopCancel := context.WithCancel(ctx)
+	return &Pool{
+		cfg:        c,
+		queue:      make(chan job, c.QueueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+		loopCtx:    loopCtx,
+		loopCancel: loopCancel,
+		status:     newStatusRegistry(),
+	}
+}
+
+func (p *Pool) Submit(name string, task Task) error {
+	p.started.Do(p.start)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	select {
+	case p.queue <- job{name: name, task: task}:
+		p.cfg.Metrics.Gauge("background_tasks_queued").Inc()
+		return nil
+	default:
+		p.cfg.Metrics.Counter("background_tasks_rejected_total", "name", name).Inc()
+		return ErrPoolFull
+	}
+}
+
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.queue)
+	p.loopCancel()
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		p.loops.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) start() {
+	for i := 0; i < p.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for j := range p.queue {
+		p.cfg.Metrics.Gauge("background_tasks_queued").Dec()
+		p.run(p.ctx, j)
+	}
+}
+
+func (p *Pool) run(ctx context.Context, j job) {
+	p.status.begin(j.name)
+	p.cfg.Metrics.Gauge("background_tasks_running").Inc()
+	start := p.cfg.Clock.Now()
+	err := p.safeRun(ctx, j)
+	duration := p.cfg.Clock.Since(start)
+	p.cfg.Metrics.Gauge("background_tasks_running").Dec()
+	p.status.end(j.name, start, duration, err)
+
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+		p.cfg.Logger.Error("background task failed", "task", j.name, "error", err.Error())
+	}
+	p.cfg.Metrics.Counter("background_tasks_total", "name", j.name, "outcome", outcome).Inc()
+	p.cfg.Metrics.Histogram("background_task_duration_seconds", "name", j.name).Observe(duration.Seconds())
+}
+
+func (p *Pool) safeRun(ctx context.Context, j job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 4096)
+			buf = buf[:runtime.Stack(buf, false)]
+			p.cfg.Logger.Error("background task panicked", "task", j.name, "panic", fmt.Sprint(r), "stack", string(buf))
+			err = fmt.Errorf("worker: task %q panicked: %v", j.name, r)
+		}
+	}()
+	return j.task(ctx)
+}